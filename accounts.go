@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"whatsapp-bot/core"
+)
+
+// multiAccountFail ends a multi-account start that can't continue: with
+// status 2 in headless mode, like headlessFail, and 1 otherwise
+func multiAccountFail(format string, args ...interface{}) {
+	if core.Runtime().Headless {
+		headlessFail(format, args...)
+	}
+	fmt.Printf(ColorReset+ColorBold+ColorYellow+"❌ Mode multi akun: "+format+ColorReset+"\n", args...)
+	os.Exit(1)
+}
+
+// multiAccountStartup resolves the main accounts of a multi-account run and
+// their pairing method. The accounts are the accounts list or, without one,
+// every valid session in bossbot/. The pairing method is only needed when a
+// listed account has no valid session yet; it comes from pairing_method or,
+// outside headless mode, is asked once for all of them.
+func multiAccountStartup() ([]string, int) {
+	accounts := core.Runtime().Accounts
+	fromConfig := len(accounts) > 0
+	if !fromConfig {
+		for _, number := range getExistingPhoneNumbers() {
+			if !isSessionValid(number) {
+				fmt.Printf("%s⚠️ Session %s tidak valid, dilewati. Tambahkan ke accounts untuk pairing ulang%s\n", ColorYellow, number, ColorReset)
+				continue
+			}
+			accounts = append(accounts, number)
+		}
+	}
+	if len(accounts) == 0 {
+		multiAccountFail("tidak ada akun. Isi accounts di config, env BOT_ACCOUNTS atau --accounts")
+	}
+
+	var needPairing []string
+	fmt.Printf("%s👥 Mode multi akun: %d akun%s\n", ColorCyan, len(accounts), ColorReset)
+	for _, number := range accounts {
+		status := ColorGreen + "session valid"
+		if fromConfig && !isSessionValid(number) {
+			if _, err := os.Stat(core.GetPaths().MainSessionDB(number)); err == nil {
+				cleanInvalidSession(number)
+			}
+			needPairing = append(needPairing, number)
+			status = ColorYellow + "perlu pairing"
+		}
+		fmt.Printf("   • %s%s%s (%s%s)\n", ColorBold, number, ColorReset, status, ColorReset)
+	}
+	fmt.Println()
+
+	pairingMethod := core.Runtime().PairingMethod
+	switch {
+	case pairingMethod != 0:
+	case len(needPairing) == 0:
+		// Only used again if a session logs out later
+		pairingMethod = 2
+	case core.Runtime().Headless:
+		headlessFail("%d akun perlu pairing tapi metode belum diatur. Isi pairing_method (1 = kode, 2 = QR) di config, env WHATSAPP_PAIRING_METHOD atau --pairing", len(needPairing))
+	default:
+		pairingMethod = askPairingMethod()
+	}
+	return accounts, pairingMethod
+}
@@ -0,0 +1,234 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+
+	"whatsapp-bot/core"
+)
+
+// targetArgs lets role commands take a number, a JID or an @mention
+var targetArgs = []ArgSpec{{Name: "nomor", Type: ArgJID, Optional: true}}
+
+func init() {
+	Register(&Command{
+		Name:        "addadmin",
+		Category:    CategoryAdmin,
+		Description: "Tambah admin bot",
+		Usage:       "6289xxx/@tag",
+		Args:        targetArgs,
+		Role:        RoleOwner,
+		Handler: func(ctx *Context) {
+			handleRoleEdit(ctx, "Admin", core.AddAdmin, true)
+		},
+	})
+
+	Register(&Command{
+		Name:        "deladmin",
+		Category:    CategoryAdmin,
+		Description: "Hapus admin bot",
+		Usage:       "6289xxx/@tag",
+		Args:        targetArgs,
+		Role:        RoleOwner,
+		Handler: func(ctx *Context) {
+			handleRoleEdit(ctx, "Admin", core.RemoveAdmin, false)
+		},
+	})
+
+	Register(&Command{
+		Name:        "addowner",
+		Category:    CategoryAdmin,
+		Description: "Tambah owner bot",
+		Usage:       "6289xxx/@tag",
+		Args:        targetArgs,
+		Role:        RoleOwner,
+		Handler: func(ctx *Context) {
+			handleRoleEdit(ctx, "Owner", core.AddOwner, true)
+		},
+	})
+
+	Register(&Command{
+		Name:        "delowner",
+		Category:    CategoryAdmin,
+		Description: "Hapus owner bot",
+		Usage:       "6289xxx/@tag",
+		Args:        targetArgs,
+		Role:        RoleOwner,
+		Handler: func(ctx *Context) {
+			handleRoleEdit(ctx, "Owner", core.RemoveOwner, false)
+		},
+	})
+
+	Register(&Command{
+		Name:        "listadmin",
+		Aliases:     []string{"admins"},
+		Category:    CategoryAdmin,
+		Description: "Lihat daftar owner & admin",
+		Role:        RoleAdmin,
+		Handler:     handleListAdminCommand,
+	})
+
+	Register(&Command{
+		Name:        "cmdrole",
+		Category:    CategoryAdmin,
+		Description: "Atur role minimum command",
+		Args: []ArgSpec{
+			{Name: "command", Type: ArgString},
+			{Name: "role", Type: ArgString, Choices: []string{"owner", "admin", "jadibot", "public", "default"}},
+		},
+		Role:    RoleOwner,
+		Handler: handleCmdRoleCommand,
+	})
+}
+
+// roleTarget finds who a role command is about: the number typed in the
+// args, the first mentioned user, or the author of the quoted message.
+func roleTarget(ctx *Context) string {
+	if ctx.Args.Has("nomor") {
+		return typedTarget(ctx, ctx.Args.JID("nomor"))
+	}
+	return messageTarget(ctx)
+}
+
+// messageContext is the context info of the command message, which holds
+// its mentions and the message it replies to
+func messageContext(ctx *Context) *waProto.ContextInfo {
+	if ctx.Message == nil {
+		return nil
+	}
+	return ctx.Message.Message.GetExtendedTextMessage().GetContextInfo()
+}
+
+// typedTarget resolves a contact typed in the args. A typed @mention only
+// carries the user part, so it is matched against the message's mention
+// list, which says whether it is a phone number or a LID.
+func typedTarget(ctx *Context, jid types.JID) string {
+	for _, raw := range messageContext(ctx).GetMentionedJID() {
+		if m, err := types.ParseJID(raw); err == nil && m.User == jid.User {
+			jid = m
+			break
+		}
+	}
+	return contactTarget(ctx, jid)
+}
+
+// messageTarget is the first mentioned user or the author of the quoted
+// message, or "" if there is neither
+func messageTarget(ctx *Context) string {
+	info := messageContext(ctx)
+	for _, raw := range info.GetMentionedJID() {
+		if jid, err := types.ParseJID(raw); err == nil {
+			return contactTarget(ctx, jid)
+		}
+	}
+	if participant := info.GetParticipant(); participant != "" {
+		if jid, err := types.ParseJID(participant); err == nil {
+			return contactTarget(ctx, jid)
+		}
+	}
+	return ""
+}
+
+// contactTarget stores a contact by phone number when it is known, so roles
+// and overrides match however the contact writes; otherwise by its JID
+func contactTarget(ctx *Context, jid types.JID) string {
+	if _, phone := SenderIDs(ctx.Client, jid); phone != "" {
+		return phone
+	}
+	return jid.ToNonAD().String()
+}
+
+func handleRoleEdit(ctx *Context, label string, edit func(string) (bool, error), adding bool) {
+	target := roleTarget(ctx)
+	if target == "" {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\nCara pakai:\n*.%s 6289xxxxxxxxx*\natau tag/reply pesan orangnya.", ctx.Command))
+		return
+	}
+
+	changed, err := edit(target)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Gagal menyimpan data role!*\n\nError: %v", err))
+		return
+	}
+
+	switch {
+	case adding && changed:
+		ctx.Reply(fmt.Sprintf("✅ *%s* sekarang menjadi %s", target, label))
+		fmt.Printf("%s✅ %s ditambahkan: %s%s\n", ColorGreen, label, target, ColorReset)
+	case adding:
+		ctx.Reply(fmt.Sprintf("ℹ️ *%s* sudah menjadi %s", target, label))
+	case changed:
+		ctx.Reply(fmt.Sprintf("✅ *%s* bukan %s lagi", target, label))
+		fmt.Printf("%s❌ %s dihapus: %s%s\n", ColorYellow, label, target, ColorReset)
+	default:
+		ctx.Reply(fmt.Sprintf("ℹ️ *%s* bukan %s", target, label))
+	}
+}
+
+func handleListAdminCommand(ctx *Context) {
+	cfg := core.GetRoleConfig()
+
+	var text strings.Builder
+	text.WriteString("👑 *DAFTAR OWNER & ADMIN*\n\n")
+
+	text.WriteString("*Owner:*\n")
+	text.WriteString("• Akun bot ini\n")
+	for _, owner := range cfg.Owners {
+		text.WriteString("• " + owner + "\n")
+	}
+
+	text.WriteString("\n*Admin:*\n")
+	if len(cfg.Admins) == 0 {
+		text.WriteString("• -\n")
+	}
+	for _, admin := range cfg.Admins {
+		text.WriteString("• " + admin + "\n")
+	}
+
+	if len(cfg.CommandRoles) > 0 {
+		text.WriteString("\n*Role Command (custom):*\n")
+		for _, cmd := range DefaultRegistry.Commands() {
+			if role, ok := cfg.CommandRoles[cmd.Name]; ok {
+				text.WriteString(fmt.Sprintf("• .%s → %s\n", cmd.Name, role))
+			}
+		}
+	}
+
+	ctx.Reply(strings.TrimSpace(text.String()))
+}
+
+func handleCmdRoleCommand(ctx *Context) {
+	name := ctx.Args.String("command")
+	cmd := DefaultRegistry.Lookup(name)
+	if cmd == nil {
+		ctx.Reply(fmt.Sprintf("❌ Command *%s* tidak ditemukan", name))
+		return
+	}
+
+	roleName := ctx.Args.String("role")
+	if roleName == "default" {
+		if err := core.SetCommandRole(cmd.Name, ""); err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Gagal menyimpan data role!*\n\nError: %v", err))
+			return
+		}
+		ctx.Reply(fmt.Sprintf("✅ Role *.%s* dikembalikan ke default (%s)", cmd.Name, roleLabel(cmd.Role)))
+		return
+	}
+
+	// Lowering an owner command (.cmdrole, .addowner, ...) would let others
+	// make themselves owner
+	role, _ := ParseRole(roleName)
+	if cmd.Role == RoleOwner && role != RoleOwner {
+		ctx.Reply(fmt.Sprintf("❌ Command *.%s* khusus owner dan tidak bisa diturunkan", cmd.Name))
+		return
+	}
+
+	if err := core.SetCommandRole(cmd.Name, role.String()); err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Gagal menyimpan data role!*\n\nError: %v", err))
+		return
+	}
+	ctx.Reply(fmt.Sprintf("✅ Command *.%s* sekarang untuk %s", cmd.Name, roleLabel(role)))
+}
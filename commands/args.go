@@ -0,0 +1,428 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mau.fi/whatsmeow/types"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
+)
+
+// ArgType is the expected type of a command argument
+type ArgType int
+
+const (
+	ArgString ArgType = iota
+	// ArgText consumes the rest of the arguments as a single string
+	ArgText
+	ArgBool
+	ArgInt
+	ArgDuration
+	ArgPhone
+	ArgJID
+)
+
+func (t ArgType) String() string {
+	switch t {
+	case ArgText:
+		return "teks"
+	case ArgBool:
+		return "on/off"
+	case ArgInt:
+		return "angka"
+	case ArgDuration:
+		return "durasi"
+	case ArgPhone:
+		return "nomor"
+	case ArgJID:
+		return "jid"
+	}
+	return "teks"
+}
+
+// ArgSpec declares one positional argument of a command
+type ArgSpec struct {
+	Name     string
+	Type     ArgType
+	Optional bool
+	// Choices, if set, restricts an ArgString to these values
+	Choices []string
+}
+
+// Arguments are the parsed arguments of a command
+type Arguments struct {
+	Raw        string
+	Positional []string
+	Flags      map[string]string
+	values     map[string]interface{}
+}
+
+// UsageError is returned when the arguments don't match the command's specs
+type UsageError struct {
+	Msg string
+}
+
+func (e *UsageError) Error() string {
+	return e.Msg
+}
+
+func usageErrorf(format string, args ...interface{}) *UsageError {
+	return &UsageError{Msg: fmt.Sprintf(format, args...)}
+}
+
+// Tokenize splits text into words, keeping "double" or 'single' quoted
+// strings together and honouring backslash escapes. A quote only opens a
+// quoted string at the start of a word, so apostrophes inside free text
+// (Budi's) are kept as they are.
+func Tokenize(text string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	var quote rune
+	inToken := false
+	escaped := false
+
+	for _, r := range text {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case !inToken && (r == '"' || r == '\''):
+			quote = r
+			inToken = true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, usageErrorf("tanda kutip %c belum ditutup", quote)
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// ParseBool accepts on/off, true/false, 1/0 and aktif/mati
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "1", "aktif":
+		return true, nil
+	case "off", "false", "0", "mati":
+		return false, nil
+	}
+	return false, usageErrorf("nilai %q bukan on/off", value)
+}
+
+var durationPartRe = regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|w|d|h|m|s)`)
+
+// ParseDuration parses durations like 10m, 1h30m, 7d or 2w
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, usageErrorf("durasi kosong")
+	}
+
+	matches := durationPartRe.FindAllStringSubmatchIndex(value, -1)
+	var total time.Duration
+	pos := 0
+	for _, m := range matches {
+		if m[0] != pos {
+			break
+		}
+		amount, err := strconv.ParseFloat(value[m[2]:m[3]], 64)
+		if err != nil {
+			return 0, usageErrorf("durasi %q tidak valid", value)
+		}
+		unit := time.Second
+		switch value[m[4]:m[5]] {
+		case "ms":
+			unit = time.Millisecond
+		case "m":
+			unit = time.Minute
+		case "h":
+			unit = time.Hour
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		}
+		total += time.Duration(amount * float64(unit))
+		pos = m[1]
+	}
+
+	if pos != len(value) || len(matches) == 0 {
+		return 0, usageErrorf("durasi %q tidak valid (contoh: 30s, 10m, 2h, 7d)", value)
+	}
+	return total, nil
+}
+
+// NormalizePhone validates a phone number with libphonenumber and returns
+// it in international form without "+". Local numbers (e.g. 0812xxx) are
+// read as numbers of the configured default_region.
+func NormalizePhone(value string) (string, error) {
+	phone, err := utils.ParsePhoneNumber(value, core.Runtime().DefaultRegion)
+	if err != nil {
+		return "", usageErrorf("%v", err)
+	}
+	return phone.Number, nil
+}
+
+// ParseJIDArg accepts a full JID, an @mention or a phone number
+func ParseJIDArg(value string) (types.JID, error) {
+	value = strings.TrimSpace(value)
+	mention := strings.HasPrefix(value, "@")
+	value = strings.TrimPrefix(value, "@")
+	if strings.Contains(value, "@") {
+		jid, err := types.ParseJID(value)
+		if err != nil {
+			return types.JID{}, usageErrorf("JID %q tidak valid", value)
+		}
+		return jid, nil
+	}
+
+	// @mentions carry the user part as WhatsApp wrote it, which may be a
+	// LID rather than a phone number. A known LID becomes a @lid JID;
+	// commands with the message at hand check its mention list as well
+	// (see roleTarget).
+	if mention {
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return types.JID{}, usageErrorf("mention %q tidak valid", "@"+value)
+		}
+		if lid := types.NewJID(value, types.HiddenUserServer); utils.GetPNForLID(lid.String()) != "" {
+			return lid, nil
+		}
+		return types.NewJID(value, types.DefaultUserServer), nil
+	}
+
+	number, err := NormalizePhone(value)
+	if err != nil {
+		return types.JID{}, err
+	}
+	return types.NewJID(number, types.DefaultUserServer), nil
+}
+
+func isPhoneToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if !(r >= '0' && r <= '9') && !strings.ContainsRune("+-().", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func countDigits(tokens []string) int {
+	count := 0
+	for _, token := range tokens {
+		for _, r := range token {
+			if r >= '0' && r <= '9' {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ParseArgs parses raw against specs. Tokens of the form --name=value (or a
+// bare --name, meaning "true") are collected as flags. A nil specs slice
+// skips validation and only tokenizes.
+func ParseArgs(raw string, specs []ArgSpec) (*Arguments, error) {
+	args := &Arguments{
+		Raw:    raw,
+		Flags:  make(map[string]string),
+		values: make(map[string]interface{}),
+	}
+
+	tokens, err := Tokenize(raw)
+	if err != nil {
+		return args, err
+	}
+
+	for _, token := range tokens {
+		if strings.HasPrefix(token, "--") && len(token) > 2 {
+			name, value, hasValue := strings.Cut(token[2:], "=")
+			if !hasValue {
+				value = "true"
+			}
+			args.Flags[strings.ToLower(name)] = value
+			continue
+		}
+		args.Positional = append(args.Positional, token)
+	}
+
+	if specs == nil {
+		return args, nil
+	}
+
+	rest := args.Positional
+	for _, spec := range specs {
+		if len(rest) == 0 {
+			if !spec.Optional {
+				return args, usageErrorf("argumen <%s> wajib diisi", spec.Name)
+			}
+			continue
+		}
+
+		var token string
+		switch spec.Type {
+		case ArgText:
+			token = strings.Join(rest, " ")
+			rest = nil
+		case ArgPhone:
+			// "+62 896-8100-8411" arrives as several tokens; keep joining
+			// until it has enough digits to be a full number
+			n := 1
+			if isPhoneToken(rest[0]) {
+				for n < len(rest) && isPhoneToken(rest[n]) && countDigits(rest[:n]) < 10 {
+					n++
+				}
+			}
+			token = strings.Join(rest[:n], " ")
+			rest = rest[n:]
+		default:
+			token = rest[0]
+			rest = rest[1:]
+		}
+
+		value, err := convertArg(spec, token)
+		if err != nil {
+			return args, err
+		}
+		args.values[spec.Name] = value
+	}
+
+	if len(rest) > 0 {
+		return args, usageErrorf("argumen berlebih: %s", strings.Join(rest, " "))
+	}
+	return args, nil
+}
+
+func convertArg(spec ArgSpec, token string) (interface{}, error) {
+	switch spec.Type {
+	case ArgBool:
+		return ParseBool(token)
+	case ArgInt:
+		n, err := strconv.Atoi(token)
+		if err != nil {
+			return nil, usageErrorf("<%s> harus angka, bukan %q", spec.Name, token)
+		}
+		return n, nil
+	case ArgDuration:
+		return ParseDuration(token)
+	case ArgPhone:
+		return NormalizePhone(token)
+	case ArgJID:
+		return ParseJIDArg(token)
+	}
+
+	if len(spec.Choices) > 0 {
+		lower := strings.ToLower(token)
+		for _, choice := range spec.Choices {
+			if lower == choice {
+				return choice, nil
+			}
+		}
+		return nil, usageErrorf("<%s> harus salah satu dari: %s", spec.Name, strings.Join(spec.Choices, ", "))
+	}
+	return token, nil
+}
+
+// Has reports whether an argument was given
+func (a *Arguments) Has(name string) bool {
+	_, ok := a.values[name]
+	return ok
+}
+
+func (a *Arguments) String(name string) string {
+	s, _ := a.values[name].(string)
+	return s
+}
+
+func (a *Arguments) Bool(name string) bool {
+	b, _ := a.values[name].(bool)
+	return b
+}
+
+func (a *Arguments) Int(name string) int {
+	n, _ := a.values[name].(int)
+	return n
+}
+
+func (a *Arguments) Duration(name string) time.Duration {
+	d, _ := a.values[name].(time.Duration)
+	return d
+}
+
+func (a *Arguments) Phone(name string) string {
+	return a.String(name)
+}
+
+func (a *Arguments) JID(name string) types.JID {
+	jid, _ := a.values[name].(types.JID)
+	return jid
+}
+
+// Flag returns the value of --name, or "" if it wasn't given
+func (a *Arguments) Flag(name string) string {
+	return a.Flags[strings.ToLower(name)]
+}
+
+// BoolFlag returns the value of --name as a bool, or def if not given
+func (a *Arguments) BoolFlag(name string, def bool) bool {
+	value, ok := a.Flags[strings.ToLower(name)]
+	if !ok {
+		return def
+	}
+	b, err := ParseBool(value)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// UsageFor builds a usage line from the command's specs, unless the
+// command sets Usage explicitly
+func UsageFor(cmd *Command) string {
+	if cmd.Usage != "" || len(cmd.Args) == 0 {
+		return cmd.Usage
+	}
+
+	parts := make([]string, 0, len(cmd.Args))
+	for _, spec := range cmd.Args {
+		name := spec.Name
+		if len(spec.Choices) > 0 {
+			name = strings.Join(spec.Choices, "/")
+		}
+		if spec.Optional {
+			parts = append(parts, "["+name+"]")
+		} else {
+			parts = append(parts, "<"+name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "  a   b\tc ", want: []string{"a", "b", "c"}},
+		{in: `label "nama panjang" x`, want: []string{"label", "nama panjang", "x"}},
+		{in: `'satu dua'`, want: []string{"satu dua"}},
+		{in: `""`, want: []string{""}},
+		{in: `a\ b`, want: []string{"a b"}},
+		{in: `\"x`, want: []string{`"x`}},
+		{in: `"kata \"kutip\""`, want: []string{`kata "kutip"`}},
+		{in: "Budi's bot", want: []string{"Budi's", "bot"}},
+		{in: `a"b c"`, want: []string{`a"b`, `c"`}},
+		{in: `"Budi's bot"`, want: []string{"Budi's bot"}},
+		{in: `"belum ditutup`, wantErr: true},
+		{in: `'belum`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Tokenize(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Tokenize(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Tokenize(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30s", want: 30 * time.Second},
+		{in: "10m", want: 10 * time.Minute},
+		{in: "2h", want: 2 * time.Hour},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: "2w", want: 14 * 24 * time.Hour},
+		{in: "500ms", want: 500 * time.Millisecond},
+		{in: "1.5h", want: 90 * time.Minute},
+		{in: " 10M ", want: 10 * time.Minute},
+		{in: "", wantErr: true},
+		{in: "10", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "10x", wantErr: true},
+		{in: "1h 30m", wantErr: true},
+		{in: "h1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	jadibotArgs := []ArgSpec{
+		{Name: "nomor", Type: ArgPhone},
+		{Name: "durasi", Type: ArgDuration, Optional: true},
+	}
+	setArgs := []ArgSpec{
+		{Name: "key", Type: ArgString},
+		{Name: "value", Type: ArgBool},
+	}
+	labelArgs := []ArgSpec{
+		{Name: "nomor", Type: ArgPhone},
+		{Name: "mode", Type: ArgString, Choices: []string{"label", "owner"}},
+		{Name: "teks", Type: ArgText},
+	}
+
+	tests := []struct {
+		name      string
+		raw       string
+		specs     []ArgSpec
+		want      map[string]interface{}
+		wantPos   []string
+		wantFlags map[string]string
+		wantErr   bool
+	}{
+		{
+			name:  "phone split over tokens is joined",
+			raw:   "+62 896-8100-8411 7d",
+			specs: jadibotArgs,
+			want:  map[string]interface{}{"nomor": "6289681008411", "durasi": 7 * 24 * time.Hour},
+		},
+		{
+			name:  "local phone gets the country code",
+			raw:   "0812-3456-7890",
+			specs: jadibotArgs,
+			want:  map[string]interface{}{"nomor": "6281234567890"},
+		},
+		{
+			name:  "joining stops once the number is long enough",
+			raw:   "6281234567890 30m",
+			specs: jadibotArgs,
+			want:  map[string]interface{}{"nomor": "6281234567890", "durasi": 30 * time.Minute},
+		},
+		{
+			name:    "required argument missing",
+			raw:     "",
+			specs:   jadibotArgs,
+			wantErr: true,
+		},
+		{
+			name:    "invalid phone",
+			raw:     "abc",
+			specs:   jadibotArgs,
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			raw:     "6281234567890 besok",
+			specs:   jadibotArgs,
+			wantErr: true,
+		},
+		{
+			name:    "extra arguments",
+			raw:     "6281234567890 1h lagi",
+			specs:   jadibotArgs,
+			wantErr: true,
+		},
+		{
+			name:      "flags are taken out of the positionals",
+			raw:       "autoread --all on --mode=grup",
+			specs:     setArgs,
+			want:      map[string]interface{}{"key": "autoread", "value": true},
+			wantPos:   []string{"autoread", "on"},
+			wantFlags: map[string]string{"all": "true", "mode": "grup"},
+		},
+		{
+			name:    "bool accepts off",
+			raw:     "autoread mati",
+			specs:   setArgs,
+			want:    map[string]interface{}{"key": "autoread", "value": false},
+			wantPos: []string{"autoread", "mati"},
+		},
+		{
+			name:    "invalid bool",
+			raw:     "autoread mungkin",
+			specs:   setArgs,
+			wantErr: true,
+		},
+		{
+			name:  "choices are matched case-insensitively and text takes the rest",
+			raw:   `6281234567890 LABEL Bot Budi's "kantor pusat"`,
+			specs: labelArgs,
+			want:  map[string]interface{}{"nomor": "6281234567890", "mode": "label", "teks": "Bot Budi's kantor pusat"},
+		},
+		{
+			name:    "value outside choices",
+			raw:     "6281234567890 warna merah",
+			specs:   labelArgs,
+			wantErr: true,
+		},
+		{
+			name:    "nil specs only tokenize",
+			raw:     `a "b c" --x`,
+			specs:   nil,
+			want:    map[string]interface{}{},
+			wantPos: []string{"a", "b c"},
+		},
+		{
+			name:  "jid argument",
+			raw:   "6281234567890@s.whatsapp.net",
+			specs: []ArgSpec{{Name: "nomor", Type: ArgJID}},
+			want:  map[string]interface{}{"nomor": types.NewJID("6281234567890", types.DefaultUserServer)},
+		},
+		{
+			name:  "int argument",
+			raw:   "42",
+			specs: []ArgSpec{{Name: "n", Type: ArgInt}},
+			want:  map[string]interface{}{"n": 42},
+		},
+		{
+			name:    "invalid int",
+			raw:     "empat",
+			specs:   []ArgSpec{{Name: "n", Type: ArgInt}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := ParseArgs(tt.raw, tt.specs)
+			if tt.wantErr {
+				var usage *UsageError
+				if !errors.As(err, &usage) {
+					t.Fatalf("ParseArgs(%q) error = %v, want *UsageError", tt.raw, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArgs(%q) error: %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(args.values, tt.want) {
+				t.Errorf("values = %#v, want %#v", args.values, tt.want)
+			}
+			if tt.wantPos != nil && !reflect.DeepEqual(args.Positional, tt.wantPos) {
+				t.Errorf("Positional = %q, want %q", args.Positional, tt.wantPos)
+			}
+			if tt.wantFlags != nil && !reflect.DeepEqual(args.Flags, tt.wantFlags) {
+				t.Errorf("Flags = %q, want %q", args.Flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestParseJIDArg(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    types.JID
+		wantErr bool
+	}{
+		{in: "6281234567890", want: types.NewJID("6281234567890", types.DefaultUserServer)},
+		{in: "0812 3456 7890", want: types.NewJID("6281234567890", types.DefaultUserServer)},
+		{in: "@6281234567890", want: types.NewJID("6281234567890", types.DefaultUserServer)},
+		{in: "123456789@lid", want: types.NewJID("123456789", types.HiddenUserServer)},
+		{in: "120363000000000000@g.us", want: types.NewJID("120363000000000000", types.GroupServer)},
+		{in: "@abc", wantErr: true},
+		{in: "bukan nomor", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseJIDArg(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseJIDArg(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseJIDArg(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseJIDArg(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
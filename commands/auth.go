@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+	"whatsapp-bot/utils"
+)
+
+// ParseRole converts a role name as typed in chat into a Role
+func ParseRole(name string) (Role, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "public", "publik", "umum":
+		return RolePublic, true
+	case "jadibot":
+		return RoleJadibot, true
+	case "admin":
+		return RoleAdmin, true
+	case "owner":
+		return RoleOwner, true
+	}
+	return RolePublic, false
+}
+
+// EffectiveRole returns the minimum role for cmd, honouring overrides
+// stored with .cmdrole. Owner commands always stay owner-only, even if
+// roles.json says otherwise.
+func EffectiveRole(cmd *Command) Role {
+	if cmd.Role == RoleOwner {
+		return RoleOwner
+	}
+	if stored, ok := core.GetCommandRole(cmd.Name); ok {
+		if role, ok := ParseRole(stored); ok {
+			return role
+		}
+	}
+	return cmd.Role
+}
+
+// SenderIDs returns every identifier the sender may be stored under: the
+// phone number digits and, for LID senders, the LID JID as well.
+func SenderIDs(client *whatsmeow.Client, sender types.JID) (ids []string, phone string) {
+	return utils.ContactIDs(client, sender)
+}
+
+// ResolveRole decides the role of the sender of msg. The bot account itself
+// is always an owner.
+func ResolveRole(client *whatsmeow.Client, msg *events.Message) (Role, string) {
+	ids, phone := SenderIDs(client, msg.Info.Sender)
+
+	botJID := client.Store.ID
+	if msg.Info.IsFromMe ||
+		utils.IsSelfMessage(client, msg.Info.Sender) ||
+		(botJID != nil && (msg.Info.Sender.User == botJID.User || msg.Info.Chat.User == botJID.User)) {
+		return RoleOwner, phone
+	}
+
+	if core.IsOwner(ids...) {
+		return RoleOwner, phone
+	}
+	if core.IsAdmin(ids...) {
+		return RoleAdmin, phone
+	}
+	if phone != "" && features.GetJadibotManager().IsSessionExists(phone) {
+		return RoleJadibot, phone
+	}
+	return RolePublic, phone
+}
+
+// roleLabel is the name of a role as shown in chat replies
+func roleLabel(r Role) string {
+	switch r {
+	case RoleOwner:
+		return "Owner"
+	case RoleAdmin:
+		return "Admin"
+	case RoleJadibot:
+		return "User Jadibot"
+	}
+	return "Publik"
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "bot",
+		Category:    CategoryGeneral,
+		Description: "Cek bot aktif",
+		Role:        RolePublic,
+		NoPrefix:    true,
+		Scope:       ScopeBoth,
+		Handler:     handleBotCommand,
+	})
+}
+
+func handleBotCommand(ctx *Context) {
+	if err := ctx.Reply("AKTIF bang"); err != nil {
+		fmt.Printf("%s⚠️ Failed to send BOT response: %v%s\n", ColorYellow, err, ColorReset)
+	} else {
+		fmt.Printf("%s✅ Responded to BOT command%s\n", ColorGreen, ColorReset)
+	}
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+)
+
+func init() {
+	// Every BotConfig field with a cmd tag also gets a shortcut command,
+	// e.g. .online on/off
+	for _, field := range core.ConfigFields() {
+		if field.Command != "" {
+			registerFieldShortcut(field)
+		}
+	}
+
+	Register(&Command{
+		Name:        "set",
+		Category:    CategoryFeature,
+		Description: "Ubah setting bot",
+		Args: []ArgSpec{
+			{Name: "key", Type: ArgString},
+			{Name: "value", Type: ArgText},
+		},
+		Role:    RoleAdmin,
+		Handler: handleSetCommand,
+	})
+
+	Register(&Command{
+		Name:        "get",
+		Category:    CategoryFeature,
+		Description: "Lihat setting bot",
+		Args:        []ArgSpec{{Name: "key", Type: ArgString, Optional: true}},
+		Role:        RoleAdmin,
+		Handler:     handleGetCommand,
+	})
+}
+
+// parseFieldValue converts text typed in chat into a value for field
+func parseFieldValue(field core.ConfigField, raw string) (interface{}, error) {
+	switch {
+	case field.IsBool():
+		return ParseBool(raw)
+	case field.IsDuration():
+		return ParseDuration(raw)
+	case field.Type.Kind() == reflect.String:
+		return raw, nil
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return nil, usageErrorf("nilai %q bukan angka", raw)
+	}
+	return reflect.ValueOf(n).Convert(field.Type).Interface(), nil
+}
+
+// fieldStatus renders a field's value with a ✅/❌ icon for toggles
+func fieldStatus(field core.ConfigField, value interface{}) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return "✅ " + field.Format(b)
+		}
+		return "❌ " + field.Format(b)
+	}
+	return field.Format(value)
+}
+
+// storeField saves value in the main account's config, or inside a jadibot
+// session in that session's own settings, and returns the previous value
+func storeField(ctx *Context, field core.ConfigField, value interface{}) (interface{}, error) {
+	if ctx.Session == "" {
+		_, old, err := core.SetAccountValue(ctx.Account, field.Key, value)
+		return old, err
+	}
+	before, _, err := features.GetJadibotManager().UpdateSessionSettings(ctx.Session, core.ConfigPatch{field.Key: value})
+	return field.Get(before), err
+}
+
+// applyField stores the value and replies in the same format for every field
+func applyField(ctx *Context, field core.ConfigField, raw string) {
+	value, err := parseFieldValue(field, raw)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Nilai tidak valid!*\n\n%v\n\nTipe: %s", err, field.TypeName()))
+		return
+	}
+
+	old, err := storeField(ctx, field, value)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Gagal mengubah %s!*\n\n%v", field.Label, err))
+		return
+	}
+
+	if b, ok := value.(bool); ok {
+		state := "DIAKTIFKAN"
+		icon := "✅"
+		color := ColorGreen
+		logState := "enabled"
+		if !b {
+			state = "DINONAKTIFKAN"
+			icon = "❌"
+			color = ColorYellow
+			logState = "disabled"
+		}
+
+		reply := fmt.Sprintf("%s %s %s", icon, field.Label, state)
+		if text := field.Format(b); text != "ON" && text != "OFF" {
+			reply += " → " + text
+		}
+		ctx.Reply(reply)
+		fmt.Printf("%s%s %s%s %s%s\n", color, icon, core.AccountTag(ctx.Account), field.Label, logState, ColorReset)
+		return
+	}
+
+	ctx.Reply(fmt.Sprintf("✅ %s diubah\n\n`%s` → `%s`", field.Label, field.Format(old), field.Format(value)))
+	fmt.Printf("%s✅ %s%s: %s → %s%s\n", ColorGreen, core.AccountTag(ctx.Account), field.Label, field.Format(old), field.Format(value), ColorReset)
+}
+
+func registerFieldShortcut(field core.ConfigField) {
+	args := []ArgSpec{{Name: "value", Type: ArgText}}
+	usage := "<" + field.TypeName() + ">"
+	if field.IsBool() {
+		args = []ArgSpec{{Name: "value", Type: ArgBool}}
+		usage = "on/off"
+	}
+
+	cmd := &Command{
+		Name:        field.Command,
+		Category:    CategoryFeature,
+		Description: field.Label,
+		Usage:       usage,
+		Args:        args,
+		Role:        RoleAdmin,
+		NoPrefix:    true,
+		Status: func(cfg core.BotConfig) string {
+			return fieldStatus(field, field.Get(cfg))
+		},
+		Handler: func(ctx *Context) {
+			applyField(ctx, field, ctx.Args.Raw)
+		},
+	}
+	// Per-session settings can also be changed by a jadibot account for
+	// its own session
+	if field.PerSession {
+		cmd.Scope = ScopeBoth
+	}
+	Register(cmd)
+}
+
+func handleSetCommand(ctx *Context) {
+	field, ok := core.LookupConfigField(ctx.Args.String("key"))
+	if !ok {
+		ctx.Reply(fmt.Sprintf("❌ Setting *%s* tidak dikenal.\n\nKetik *.get* untuk melihat semua setting.", ctx.Args.String("key")))
+		return
+	}
+	applyField(ctx, field, ctx.Args.String("value"))
+}
+
+func describeField(field core.ConfigField, cfg core.BotConfig) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("%s *%s* (`%s`)\n", field.Icon, field.Label, field.Key))
+	text.WriteString(fmt.Sprintf("   Nilai: %s\n", fieldStatus(field, field.Get(cfg))))
+	text.WriteString(fmt.Sprintf("   Tipe: %s", field.TypeName()))
+	if r := field.RangeText(); r != "" {
+		text.WriteString(fmt.Sprintf(" (%s)", r))
+	}
+	text.WriteString("\n")
+	if field.Description != "" {
+		text.WriteString(fmt.Sprintf("   _%s_\n", field.Description))
+	}
+	return text.String()
+}
+
+func handleGetCommand(ctx *Context) {
+	cfg := configFor(ctx)
+
+	if ctx.Args.Has("key") {
+		field, ok := core.LookupConfigField(ctx.Args.String("key"))
+		if !ok {
+			ctx.Reply(fmt.Sprintf("❌ Setting *%s* tidak dikenal.\n\nKetik *.get* untuk melihat semua setting.", ctx.Args.String("key")))
+			return
+		}
+		ctx.Reply(strings.TrimSpace(describeField(field, cfg)))
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString("⚙️ *SETTING BOT*\n\n")
+	for _, field := range core.ConfigFields() {
+		text.WriteString(describeField(field, cfg))
+		text.WriteString("\n")
+	}
+	text.WriteString("💡 Ubah dengan *.set <key> <nilai>*")
+	ctx.Reply(text.String())
+}
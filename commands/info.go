@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow"
@@ -21,6 +22,19 @@ const (
 	ColorYellow = "\033[33m"
 )
 
+func init() {
+	Register(&Command{
+		Name:        "info",
+		Category:    CategoryGeneral,
+		Description: "Cek status fitur",
+		Role:        RoleAdmin,
+		NoPrefix:    true,
+		Handler: func(ctx *Context) {
+			HandleInfoCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender, configFor(ctx))
+		},
+	})
+}
+
 func sendReaction(ctx context.Context, client *whatsmeow.Client, chatJID types.JID, messageID string, emoji string) {
 	reactionMsg := &waProto.Message{
 		ReactionMessage: &waProto.ReactionMessage{
@@ -50,7 +64,7 @@ func getRamInfo() (totalMB uint64, usedMB uint64, percentUsed float64) {
 }
 
 func sendBotImage(ctx context.Context, client *whatsmeow.Client, chatJID types.JID, caption string, messageID string, senderJID types.JID) error {
-	imageFile := "img/bot.png"
+	imageFile := core.GetPaths().BotImage()
 	imageData, err := os.ReadFile(imageFile)
 	if err != nil {
 		return err
@@ -86,7 +100,7 @@ func sendBotImage(ctx context.Context, client *whatsmeow.Client, chatJID types.J
 	return nil
 }
 
-func HandleInfoCommand(client *whatsmeow.Client, chatJID types.JID, messageID string, senderJID types.JID) {
+func HandleInfoCommand(client *whatsmeow.Client, chatJID types.JID, messageID string, senderJID types.JID, config core.BotConfig) {
 	ctx := context.Background()
 
 	go func() {
@@ -95,48 +109,17 @@ func HandleInfoCommand(client *whatsmeow.Client, chatJID types.JID, messageID st
 
 	time.Sleep(600 * time.Millisecond)
 
-	config := core.GetConfig()
-
-	onlineIcon := "❌"
-	onlineStatus := "OFF"
-	if config.AutoOnline {
-		onlineIcon = "✅"
-		onlineStatus = "ON"
-	}
-
-	typingIcon := "❌"
-	typingStatus := "OFF"
-	if config.AutoTyping {
-		typingIcon = "✅"
-		typingStatus = "ON"
-	}
-
-	recordIcon := "❌"
-	recordStatus := "OFF"
-	if config.AutoRecording {
-		recordIcon = "✅"
-		recordStatus = "ON"
-	}
-
-	readStoryIcon := "❌"
-	readStoryStatus := "OFF"
-	if config.AutoReadStory {
-		readStoryIcon = "✅"
-		readStoryStatus = "ON"
-	}
-
-	likeStoryIcon := "❌"
-	likeStoryStatus := "OFF"
-	if config.AutoLikeStory {
-		likeStoryIcon = "✅"
-		likeStoryStatus = "ON"
-	}
-
-	storyDelayIcon := "❌"
-	storyDelayStatus := "Normal (1s)"
-	if config.StoryRandomDelay {
-		storyDelayIcon = "✅"
-		storyDelayStatus = "Random (1-20s)"
+	var featureText strings.Builder
+	for _, field := range core.ConfigFields() {
+		value := field.Get(config)
+		icon := ""
+		if b, ok := value.(bool); ok {
+			icon = "❌"
+			if b {
+				icon = "✅"
+			}
+		}
+		featureText.WriteString(fmt.Sprintf("*> %s*\n  Status: `%s` %s\n\n", field.Label, field.Format(value), icon))
 	}
 
 	now := time.Now()
@@ -150,24 +133,7 @@ func HandleInfoCommand(client *whatsmeow.Client, chatJID types.JID, messageID st
 			"┃ *🤖 BOT STATUS*\n"+
 			"┗━━━━━━━━━━━━━━━━━━━━━━\n"+
 			"\n"+
-			"*> Auto Online*\n"+
-			"  Status: `%s` %s\n"+
-			"\n"+
-			"*> Auto Typing*\n"+
-			"  Status: `%s` %s\n"+
-			"\n"+
-			"*> Auto Recording*\n"+
-			"  Status: `%s` %s\n"+
-			"\n"+
-			"*> Auto Read Story*\n"+
-			"  Status: `%s` %s\n"+
-			"\n"+
-			"*> Auto Like Story*\n"+
-			"  Status: `%s` %s\n"+
-			"\n"+
-			"*> Story Delay*\n"+
-			"  Mode: `%s` %s\n"+
-			"\n"+
+			"%s"+
 			"*> System RAM*\n"+
 			"  Total: `%d MB`\n"+
 			"  Used: `%d MB` (%.1f%%)\n"+
@@ -178,15 +144,11 @@ func HandleInfoCommand(client *whatsmeow.Client, chatJID types.JID, messageID st
 			"└─────────────────────\n"+
 			"\n"+
 			"_💡 Use *.menu* for more commands_",
-		onlineStatus, onlineIcon,
-		typingStatus, typingIcon,
-		recordStatus, recordIcon,
-		readStoryStatus, readStoryIcon,
-		likeStoryStatus, likeStoryIcon,
-		storyDelayStatus, storyDelayIcon,
+		featureText.String(),
 		totalRAM, usedRAM, ramPercent,
 		currentTime, currentDate)
 
+	infoText = tagReply(client, infoText)
 	imgErr := sendBotImage(ctx, client, chatJID, infoText, messageID, senderJID)
 	if imgErr != nil {
 		replyMsg := &waProto.Message{
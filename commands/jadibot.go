@@ -0,0 +1,403 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+)
+
+// phoneArgs is an optional phone number; without it the handlers show help
+var phoneArgs = []ArgSpec{{Name: "nomor", Type: ArgPhone, Optional: true}}
+
+func init() {
+	// Args are parsed by handleJadibotCommand: either a registration or
+	// one of jadibotActions
+	Register(&Command{
+		Name:        "jadibot",
+		Category:    CategoryJadibot,
+		Description: "Daftar/atur jadibot",
+		Usage:       "6289xxx [qr] [--durasi=30d] | extend 6289xxx <durasi/permanen> | pause/resume 6289xxx",
+		Role:        RoleAdmin,
+		NoPrefix:    true,
+		Handler:     handleJadibotCommand,
+	})
+
+	Register(&Command{
+		Name:        "listjadibot",
+		Category:    CategoryJadibot,
+		Description: "Lihat daftar jadibot",
+		Role:        RoleJadibot,
+		NoPrefix:    true,
+		Handler: func(ctx *Context) {
+			features.HandleListJadibotCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender, jadibotScope(ctx))
+			fmt.Printf("%s📋 List jadibot command executed%s\n", ColorCyan, ColorReset)
+		},
+	})
+
+	Register(&Command{
+		Name:        "deljadibot",
+		Category:    CategoryJadibot,
+		Description: "Hapus jadibot",
+		Usage:       "6289xxx",
+		Args:        phoneArgs,
+		Role:        RoleJadibot,
+		NoPrefix:    true,
+		Handler: func(ctx *Context) {
+			number := ctx.Args.Phone("nomor")
+			if !canManageJadibot(ctx, number) {
+				return
+			}
+			features.HandleDelJadibotCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender, number)
+			fmt.Printf("%s🗑️ Delete jadibot command executed%s\n", ColorCyan, ColorReset)
+		},
+	})
+
+	Register(&Command{
+		Name:        "jadibotinfo",
+		Category:    CategoryJadibot,
+		Description: "Info detail jadibot",
+		Usage:       "[6289xxx]",
+		Args:        phoneArgs,
+		Role:        RoleJadibot,
+		NoPrefix:    true,
+		Handler:     handleJadibotInfoCommand,
+	})
+
+	Register(&Command{
+		Name:        "jadibotset",
+		Category:    CategoryJadibot,
+		Description: "Setting khusus jadibot",
+		Usage:       "6289xxx [key on/off/reset] [label teks]",
+		Args: []ArgSpec{
+			{Name: "nomor", Type: ArgPhone},
+			{Name: "key", Type: ArgString, Optional: true},
+			{Name: "value", Type: ArgText, Optional: true},
+		},
+		Role:    RoleAdmin,
+		Handler: handleJadibotSetCommand,
+	})
+}
+
+// registerArgs are the arguments of ".jadibot 62xxx [qr]"
+var registerArgs = []ArgSpec{
+	{Name: "nomor", Type: ArgPhone, Optional: true},
+	{Name: "metode", Type: ArgString, Optional: true, Choices: []string{"qr", "code"}},
+}
+
+// jadibotAction is a subcommand of .jadibot, e.g. ".jadibot extend 62xxx 30d"
+type jadibotAction struct {
+	usage   string
+	args    []ArgSpec
+	handler func(ctx *Context, args *Arguments)
+}
+
+var jadibotActions = map[string]jadibotAction{
+	"extend": {
+		usage: "<nomor> <durasi/permanen>",
+		args: []ArgSpec{
+			{Name: "nomor", Type: ArgPhone},
+			{Name: "durasi", Type: ArgString},
+		},
+		handler: handleJadibotExtend,
+	},
+	"pause": {
+		usage:   "<nomor>",
+		args:    []ArgSpec{{Name: "nomor", Type: ArgPhone}},
+		handler: handleJadibotPause,
+	},
+	"resume": {
+		usage:   "<nomor>",
+		args:    []ArgSpec{{Name: "nomor", Type: ArgPhone}},
+		handler: handleJadibotResume,
+	},
+}
+
+func handleJadibotCommand(ctx *Context) {
+	if len(ctx.Args.Positional) > 0 {
+		name := strings.ToLower(ctx.Args.Positional[0])
+		if action, ok := jadibotActions[name]; ok {
+			args, err := ParseArgs(strings.Join(ctx.Args.Positional[1:], " "), action.args)
+			if err != nil {
+				ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\n%v\n\nCara pakai:\n*.jadibot %s %s*", err, name, action.usage))
+				return
+			}
+			args.Flags = ctx.Args.Flags
+			action.handler(ctx, args)
+			return
+		}
+	}
+
+	args, err := ParseArgs(ctx.Args.Raw, registerArgs)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\n%v\n\nCara pakai:\n*.jadibot %s*", err, UsageFor(DefaultRegistry.Lookup("jadibot"))))
+		return
+	}
+
+	opts := features.PairingOptions{QR: args.String("metode") == "qr", Account: ctx.Account}
+	if raw := args.Flag("durasi"); raw != "" {
+		d, err := parseExpiryDuration(raw)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Durasi tidak valid!*\n\n%v", err))
+			return
+		}
+		opts.Duration = d
+		if d == 0 {
+			opts.Duration = -1
+		}
+	}
+
+	features.HandleJadibotCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender, args.Phone("nomor"), opts)
+	fmt.Printf("%s🤖 Jadibot command executed%s\n", ColorCyan, ColorReset)
+}
+
+// parseExpiryDuration reads a jadibot duration such as 30d or 12h;
+// "permanen" returns 0, meaning no expiry
+func parseExpiryDuration(raw string) (time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "permanen", "permanent", "unlimited", "selamanya":
+		return 0, nil
+	}
+	d, err := ParseDuration(raw)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, usageErrorf("durasi harus lebih dari 0")
+	}
+	return d, nil
+}
+
+func handleJadibotExtend(ctx *Context, args *Arguments) {
+	number := args.Phone("nomor")
+	if !canManageJadibot(ctx, number) {
+		return
+	}
+	d, err := parseExpiryDuration(args.String("durasi"))
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Durasi tidak valid!*\n\n%v", err))
+		return
+	}
+
+	expiresAt, err := features.GetJadibotManager().ExtendSession(number, d)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	if expiresAt.IsZero() {
+		ctx.Reply(fmt.Sprintf("✅ Jadibot *%s* sekarang tanpa batas waktu", number))
+	} else {
+		ctx.Reply(fmt.Sprintf("✅ Masa aktif jadibot *%s* diperpanjang\n\n📅 Berakhir: %s\n⏳ Sisa: %s",
+			number, features.FormatStartDateTime(expiresAt), features.FormatRemaining(expiresAt)))
+	}
+	fmt.Printf("%s⏳ Masa aktif jadibot %s: %s%s\n", ColorGreen, number, features.FormatRemaining(expiresAt), ColorReset)
+}
+
+func handleJadibotPause(ctx *Context, args *Arguments) {
+	number := args.Phone("nomor")
+	if !canManageJadibot(ctx, number) {
+		return
+	}
+	if err := features.GetJadibotManager().PauseSession(number); err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	ctx.Reply(fmt.Sprintf("⏸️ Jadibot *%s* dijeda\n\nSession tetap tersimpan. Ketik *.jadibot resume %s* untuk melanjutkan.", number, number))
+}
+
+func handleJadibotResume(ctx *Context, args *Arguments) {
+	number := args.Phone("nomor")
+	if !canManageJadibot(ctx, number) {
+		return
+	}
+	ctx.Reply(fmt.Sprintf("⏳ Menghubungkan kembali jadibot *%s*...", number))
+	if err := features.GetJadibotManager().ResumeSession(number); err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	ctx.Reply(fmt.Sprintf("▶️ Jadibot *%s* aktif kembali", number))
+}
+
+func handleJadibotInfoCommand(ctx *Context) {
+	number := ctx.Args.Phone("nomor")
+	if number == "" {
+		number = jadibotScope(ctx)
+	}
+	if number == "" {
+		ctx.Reply("❌ *Format Salah!*\n\nCara pakai:\n*.jadibotinfo 6289xxx*\n\nKetik *.listjadibot* untuk melihat nomor jadibot.")
+		return
+	}
+	if !canManageJadibot(ctx, number) {
+		return
+	}
+
+	info, err := features.GetJadibotManager().SessionInfo(number)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	ctx.Reply(info)
+}
+
+// jadibotScope returns the only jadibot number a jadibot user may see, or
+// "" when the sender may see every session
+func jadibotScope(ctx *Context) string {
+	if ctx.Role == RoleJadibot {
+		return ctx.SenderPhone
+	}
+	return ""
+}
+
+// canManageJadibot checks that a jadibot user only targets their own
+// session. Admins and owners may target any session of the main account
+// they are talking to.
+func canManageJadibot(ctx *Context, number string) bool {
+	if number == "" {
+		return true
+	}
+	if scope := jadibotScope(ctx); scope != "" && number != scope {
+		ctx.Reply(fmt.Sprintf("⛔ *Akses ditolak!*\n\nKamu hanya bisa mengatur jadibot milikmu sendiri (*%s*).", scope))
+		return false
+	}
+	if !core.MultiAccount() {
+		return true
+	}
+	if account, ok := features.GetJadibotManager().SessionAccount(number); ok && account != ctx.Account {
+		ctx.Reply(fmt.Sprintf("⛔ *Akses ditolak!*\n\nJadibot *%s* terdaftar di akun *%s*. Atur lewat akun tersebut.", number, account))
+		return false
+	}
+	return true
+}
+
+// sessionKeys lists the shortcut names of the per-session settings
+func sessionKeys() string {
+	var keys []string
+	for _, field := range core.SessionFields() {
+		keys = append(keys, field.Command)
+	}
+	return strings.Join(keys, ", ")
+}
+
+// describeSession lists a session's settings, marking which ones are its own
+// and which follow the main bot
+func describeSession(number string) string {
+	jm := features.GetJadibotManager()
+	cfg, _ := jm.SessionConfig(number)
+	own := jm.SessionSettings(number)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🤖 *SETTING JADIBOT*\n%s\n", number))
+	if session := jm.GetSession(number); session != nil && session.Label != "" {
+		text.WriteString(fmt.Sprintf("🏷️ %s\n", session.Label))
+	}
+	text.WriteString("\n")
+	for _, field := range core.SessionFields() {
+		source := "utama"
+		if _, ok := own[field.Key]; ok {
+			source = "khusus"
+		}
+		text.WriteString(fmt.Sprintf("%s %s: %s _(%s)_\n", field.Icon, field.Label, fieldStatus(field, field.Get(cfg)), source))
+	}
+	return text.String()
+}
+
+func handleJadibotSetCommand(ctx *Context) {
+	number := ctx.Args.Phone("nomor")
+	jm := features.GetJadibotManager()
+	if jm.GetSession(number) == nil {
+		ctx.Reply(fmt.Sprintf("❌ Jadibot *%s* tidak ditemukan. Ketik *.listjadibot*", number))
+		return
+	}
+	if !canManageJadibot(ctx, number) {
+		return
+	}
+
+	if !ctx.Args.Has("key") {
+		ctx.Reply(strings.TrimSpace(describeSession(number)) +
+			"\n\n💡 *.jadibotset " + number + " <key> on/off/reset*\n💡 *.jadibotset " + number + " reset* untuk ikut bot utama semua")
+		return
+	}
+
+	// ".jadibotset 62xxx reset" returns every setting to the main bot's
+	if strings.EqualFold(ctx.Args.String("key"), "reset") && !ctx.Args.Has("value") {
+		before, after, err := jm.ResetSessionSettings(number)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Gagal mengubah setting jadibot!*\n\n%v", err))
+			return
+		}
+		reply := fmt.Sprintf("✅ Semua setting jadibot *%s* kembali mengikuti bot utama", number)
+		if changes := describeChanges(before, after); changes != "" {
+			reply += "\n\n" + strings.TrimSpace(changes)
+		}
+		ctx.Reply(reply)
+		fmt.Printf("%s✅ Setting jadibot %s direset%s\n", ColorGreen, number, ColorReset)
+		return
+	}
+
+	if strings.EqualFold(ctx.Args.String("key"), "label") {
+		handleJadibotLabel(ctx, number)
+		return
+	}
+
+	field, ok := core.LookupConfigField(ctx.Args.String("key"))
+	if !ok || !field.PerSession {
+		ctx.Reply(fmt.Sprintf("❌ Setting %q tidak bisa diatur per jadibot\n\nBisa diatur: %s, label", ctx.Args.String("key"), sessionKeys()))
+		return
+	}
+	if !ctx.Args.Has("value") {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\nCara pakai:\n*.jadibotset %s %s on/off/reset*", number, field.Command))
+		return
+	}
+	value, err := parseOverrideValue(ctx.Args.String("value"))
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Nilai tidak valid!*\n\n%v", err))
+		return
+	}
+
+	var after core.BotConfig
+	if value == nil {
+		_, after, err = jm.ResetSessionSettings(number, field.Key)
+	} else {
+		_, after, err = jm.UpdateSessionSettings(number, core.ConfigPatch{field.Key: *value})
+	}
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Gagal mengubah setting jadibot!*\n\n%v", err))
+		return
+	}
+
+	status := fieldStatus(field, field.Get(after))
+	if value == nil {
+		ctx.Reply(fmt.Sprintf("✅ %s jadibot *%s* kembali mengikuti bot utama (%s)", field.Label, number, status))
+	} else {
+		ctx.Reply(fmt.Sprintf("✅ %s jadibot *%s*: %s", field.Label, number, status))
+	}
+	fmt.Printf("%s✅ Setting jadibot %s: %s=%s%s\n", ColorGreen, number, field.Key, field.Format(field.Get(after)), ColorReset)
+}
+
+// handleJadibotLabel sets or, with "reset", removes a session's label
+func handleJadibotLabel(ctx *Context, number string) {
+	label := strings.TrimSpace(ctx.Args.String("value"))
+	if label == "" {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\nCara pakai:\n*.jadibotset %s label <teks/reset>*", number))
+		return
+	}
+	if strings.EqualFold(label, "reset") {
+		label = ""
+	}
+	if len([]rune(label)) > 40 {
+		ctx.Reply("❌ Label maksimal 40 karakter")
+		return
+	}
+
+	if err := features.GetJadibotManager().SetSessionLabel(number, label); err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+	if label == "" {
+		ctx.Reply(fmt.Sprintf("✅ Label jadibot *%s* dihapus", number))
+		return
+	}
+	ctx.Reply(fmt.Sprintf("✅ Label jadibot *%s*: %s", number, label))
+}
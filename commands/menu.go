@@ -3,6 +3,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"go.mau.fi/whatsmeow"
 	waProto "go.mau.fi/whatsmeow/binary/proto"
@@ -16,83 +17,71 @@ const (
 	ColorGreen = "\033[32m"
 )
 
-func HandleMenuCommand(client *whatsmeow.Client, chatJID types.JID, messageID string, senderJID types.JID) {
-	ctx := context.Background()
-
-	config := core.GetConfig()
-
-	onlineStatus := "❌ OFF"
-	if config.AutoOnline {
-		onlineStatus = "✅ ON"
-	}
-
-	typingStatus := "❌ OFF"
-	if config.AutoTyping {
-		typingStatus = "✅ ON"
-	}
-
-	recordStatus := "❌ OFF"
-	if config.AutoRecording {
-		recordStatus = "✅ ON"
-	}
-
-	readStoryStatus := "❌ OFF"
-	if config.AutoReadStory {
-		readStoryStatus = "✅ ON"
-	}
+func init() {
+	Register(&Command{
+		Name:        "menu",
+		Category:    CategoryGeneral,
+		Description: "Lihat menu ini",
+		Role:        RolePublic,
+		NoPrefix:    true,
+		Scope:       ScopeBoth,
+		Handler: func(ctx *Context) {
+			HandleMenuCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender, ctx.Role, ctx.Session, configFor(ctx))
+		},
+	})
+}
 
-	likeStoryStatus := "❌ OFF"
-	if config.AutoLikeStory {
-		likeStoryStatus = "✅ ON"
+// BuildMenuText renders the menu from the commands in the registry that
+// role is allowed to run, or for a jadibot session the commands available
+// there. cfg is the config the command statuses are shown from.
+func BuildMenuText(r *Registry, role Role, session string, cfg core.BotConfig) string {
+	var menu strings.Builder
+	menu.WriteString("╔═══════════════════════\n")
+	if session != "" {
+		menu.WriteString("║ 🤖 JADIBOT MENU\n")
+	} else {
+		menu.WriteString("║ 🤖 BOT MENU\n")
 	}
-
-	storyDelayStatus := "❌ Normal (1s)"
-	if config.StoryRandomDelay {
-		storyDelayStatus = "✅ Random (1-20s)"
+	menu.WriteString("╚═══════════════════════\n")
+
+	grouped := r.ByCategory()
+	for _, category := range r.Categories() {
+		var allowed []*Command
+		for _, cmd := range grouped[category] {
+			if cmd.AvailableIn(session) && (session != "" || EffectiveRole(cmd) <= role) {
+				allowed = append(allowed, cmd)
+			}
+		}
+		if len(allowed) == 0 {
+			continue
+		}
+
+		menu.WriteString("\n")
+		menu.WriteString(category + ":\n")
+		for _, cmd := range allowed {
+			usage := "." + cmd.Name
+			if u := UsageFor(cmd); u != "" {
+				usage += " " + u
+			}
+
+			if cmd.Status != nil {
+				menu.WriteString(fmt.Sprintf("\n• %s: %s\n", cmd.Description, cmd.Status(cfg)))
+				menu.WriteString(fmt.Sprintf("   %s\n", usage))
+			} else {
+				menu.WriteString(fmt.Sprintf("• %s - %s\n", usage, cmd.Description))
+			}
+		}
+		menu.WriteString("\n━━━━━━━━━━━━━━━━━━━━\n")
 	}
 
-	menuText := fmt.Sprintf(`╔═══════════════════════
-║ 🤖 BOT MENU
-╚═══════════════════════
-
-📋 FITUR YANG TERSEDIA:
-
-1️⃣ Auto Online: %s
-   .online on/off
-
-2️⃣ Auto Typing: %s
-   .typing on/off
-
-3️⃣ Auto Recording: %s
-   .record on/off
-
-4️⃣ Auto Read Story: %s
-   .readstory on/off
-
-5️⃣ Auto Like Story: %s
-   .likestory on/off
-
-6️⃣ Story Delay: %s
-   .storydelay on/off
-
-━━━━━━━━━━━━━━━━━━━━
-
-📱 JADIBOT COMMANDS:
-• .jadibot 6289xxx - Daftar jadibot
-• .listjadibot - Lihat daftar jadibot
-• .deljadibot 6289xxx - Hapus jadibot
-
-━━━━━━━━━━━━━━━━━━━━
+	menu.WriteString("💡 Gunakan command untuk ubah setting!")
+	return menu.String()
+}
 
-ℹ️ COMMAND LAINNYA:
-• .info - Cek status fitur
-• .bot - Cek bot aktif
-• .status - Lihat status fitur
-• .menu - Lihat menu ini
+func HandleMenuCommand(client *whatsmeow.Client, chatJID types.JID, messageID string, senderJID types.JID, role Role, session string, cfg core.BotConfig) {
+	ctx := context.Background()
 
-━━━━━━━━━━━━━━━━━━━━
-💡 Gunakan command untuk ubah setting!`,
-		onlineStatus, typingStatus, recordStatus, readStoryStatus, likeStoryStatus, storyDelayStatus)
+	menuText := tagReply(client, BuildMenuText(DefaultRegistry, role, session, cfg))
 
 	replyMsg := &waProto.Message{
 		ExtendedTextMessage: &waProto.ExtendedTextMessage{
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"whatsapp-bot/core"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "chatset",
+		Category:    CategoryFeature,
+		Description: "Setting khusus chat ini",
+		Usage:       "[key on/off/reset] [--chat=jid] [--all]",
+		Args: []ArgSpec{
+			{Name: "key", Type: ArgString, Optional: true},
+			{Name: "value", Type: ArgString, Optional: true},
+		},
+		Role:    RoleAdmin,
+		Handler: handleChatSetCommand,
+	})
+
+	Register(&Command{
+		Name:        "contactset",
+		Category:    CategoryFeature,
+		Description: "Setting khusus kontak",
+		Usage:       "[6289xxx/@tag] [key on/off/reset]",
+		// nomor is parsed by contactSetArgs: it may be left out when the
+		// message tags or replies to the contact
+		Args: []ArgSpec{
+			{Name: "nomor", Type: ArgString, Optional: true},
+			{Name: "key", Type: ArgString, Optional: true},
+			{Name: "value", Type: ArgString, Optional: true},
+		},
+		Role:    RoleAdmin,
+		Handler: handleContactSetCommand,
+	})
+}
+
+// parseOverrideValue reads on/off, or reset/default which returns the
+// target to the global setting (nil)
+func parseOverrideValue(raw string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "reset", "default", "hapus":
+		return nil, nil
+	}
+	b, err := ParseBool(raw)
+	if err != nil {
+		return nil, usageErrorf("nilai %q harus on/off/reset", raw)
+	}
+	return &b, nil
+}
+
+// describeOverride lists every overridable setting for one target, marking
+// which ones are forced and which follow global, the main account's config
+func describeOverride(title string, override core.Override, global core.BotConfig) string {
+
+	var text strings.Builder
+	text.WriteString(title + "\n\n")
+	for _, field := range core.OverridableFields() {
+		if value, ok := override[field.Key]; ok {
+			text.WriteString(fmt.Sprintf("%s %s: %s _(khusus)_\n", field.Icon, field.Label, fieldStatus(field, value)))
+		} else {
+			text.WriteString(fmt.Sprintf("%s %s: %s _(global)_\n", field.Icon, field.Label, fieldStatus(field, field.Get(global))))
+		}
+	}
+	return text.String()
+}
+
+// describeOverrideList lists every target of table with its forced settings
+func describeOverrideList(title string, table map[string]core.Override) string {
+	var text strings.Builder
+	text.WriteString(title + "\n\n")
+	if len(table) == 0 {
+		text.WriteString("Belum ada setting khusus.\n")
+	}
+	for _, target := range core.OverrideTargets(table) {
+		var parts []string
+		for _, field := range core.OverridableFields() {
+			if value, ok := table[target][field.Key]; ok {
+				parts = append(parts, fmt.Sprintf("%s %s", field.Command, field.Format(value)))
+			}
+		}
+		text.WriteString(fmt.Sprintf("• %s\n   %s\n", target, strings.Join(parts, ", ")))
+	}
+	return text.String()
+}
+
+// applyOverride handles "<key> <value>" for either command and replies
+func applyOverride(ctx *Context, target, key, rawValue string, set func(target, key string, value *bool) (core.ConfigField, bool, error)) {
+	if rawValue == "" {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\nCara pakai:\n*.%s %s*", ctx.Command, UsageFor(DefaultRegistry.Lookup(ctx.Command))))
+		return
+	}
+	value, err := parseOverrideValue(rawValue)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Nilai tidak valid!*\n\n%v", err))
+		return
+	}
+
+	field, changed, err := set(target, key, value)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v\n\nBisa diatur: %s", err, overridableKeys()))
+		return
+	}
+
+	switch {
+	case value == nil && !changed:
+		ctx.Reply(fmt.Sprintf("ℹ️ %s untuk *%s* sudah mengikuti setting global", field.Label, target))
+	case value == nil:
+		ctx.Reply(fmt.Sprintf("✅ %s untuk *%s* kembali mengikuti setting global", field.Label, target))
+		fmt.Printf("%s✅ Override %s dihapus: %s%s\n", ColorGreen, field.Key, target, ColorReset)
+	default:
+		ctx.Reply(fmt.Sprintf("✅ %s untuk *%s*: %s", field.Label, target, fieldStatus(field, *value)))
+		if changed {
+			fmt.Printf("%s✅ Override %s=%s: %s%s\n", ColorGreen, field.Key, field.Format(*value), target, ColorReset)
+		}
+	}
+}
+
+func overridableKeys() string {
+	var keys []string
+	for _, field := range core.OverridableFields() {
+		keys = append(keys, field.Command)
+	}
+	return strings.Join(keys, ", ")
+}
+
+func handleChatSetCommand(ctx *Context) {
+	if ctx.Args.BoolFlag("all", false) {
+		ctx.Reply(strings.TrimSpace(describeOverrideList("💬 *SETTING KHUSUS CHAT*", core.GetOverrides().Chats)))
+		return
+	}
+
+	chat := ctx.Chat.ToNonAD()
+	if raw := ctx.Args.Flag("chat"); raw != "" {
+		jid, err := ParseJIDArg(raw)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Chat tidak valid!*\n\n%v", err))
+			return
+		}
+		chat = jid.ToNonAD()
+	}
+	target := chat.String()
+
+	if !ctx.Args.Has("key") {
+		title := fmt.Sprintf("💬 *SETTING CHAT*\n%s", target)
+		ctx.Reply(strings.TrimSpace(describeOverride(title, core.GetOverrides().Chats[target], configFor(ctx))) +
+			"\n\n💡 *.chatset <key> on/off/reset*")
+		return
+	}
+	applyOverride(ctx, target, ctx.Args.String("key"), ctx.Args.String("value"), core.SetChatOverride)
+}
+
+// contactSetArgs splits the args of .contactset into the contact, key and
+// value. The contact may be left out when the message tags or replies to
+// them (".contactset typing off" as a reply); the first word is the key then.
+func contactSetArgs(ctx *Context) (target, key, value string, err error) {
+	key, value = ctx.Args.String("key"), ctx.Args.String("value")
+	if !ctx.Args.Has("nomor") {
+		return messageTarget(ctx), key, value, nil
+	}
+
+	raw := ctx.Args.String("nomor")
+	jid, err := ParseJIDArg(raw)
+	if err == nil {
+		return typedTarget(ctx, jid), key, value, nil
+	}
+	target = messageTarget(ctx)
+	if target == "" || ctx.Args.Has("value") {
+		return "", "", "", err
+	}
+	return target, raw, key, nil
+}
+
+func handleContactSetCommand(ctx *Context) {
+	target, key, value, err := contactSetArgs(ctx)
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\n%v\n\nCara pakai:\n*.%s %s*\natau tag/reply pesan kontaknya", err, ctx.Command, UsageFor(DefaultRegistry.Lookup(ctx.Command))))
+		return
+	}
+	if target == "" {
+		ctx.Reply(strings.TrimSpace(describeOverrideList("👤 *SETTING KHUSUS KONTAK*", core.GetOverrides().Contacts)) +
+			"\n\n💡 *.contactset 6289xxx <key> on/off/reset*\natau reply/tag kontaknya: *.contactset <key> on/off/reset*")
+		return
+	}
+
+	if key == "" {
+		title := fmt.Sprintf("👤 *SETTING KONTAK*\n%s", target)
+		ctx.Reply(strings.TrimSpace(describeOverride(title, core.GetOverrides().Contacts[target], configFor(ctx))))
+		return
+	}
+	applyOverride(ctx, target, key, value, core.SetContactOverride)
+}
@@ -2,14 +2,27 @@ package commands
 
 import (
 	"strings"
+	"unicode"
 )
 
 // CommandPrefixes defines all supported prefixes
 var CommandPrefixes = []string{".", "!", "-", "/"}
 
+// splitCommand separates the command name from the raw argument text.
+// The arguments are returned untouched so quotes and spacing survive for
+// ParseArgs.
+func splitCommand(content string) (string, string) {
+	content = strings.TrimSpace(content)
+	idx := strings.IndexFunc(content, unicode.IsSpace)
+	if idx < 0 {
+		return strings.ToLower(content), ""
+	}
+	return strings.ToLower(content[:idx]), strings.TrimSpace(content[idx:])
+}
+
 // ParseCommand extracts command name and args from message text
 // Supports multiple prefixes: ., !, -, / and no prefix
-// Returns (commandName, args, isCommand)
+// Returns (commandName, rawArgs, isCommand)
 func ParseCommand(text string) (string, string, bool) {
 	text = strings.TrimSpace(text)
 	if text == "" {
@@ -19,58 +32,36 @@ func ParseCommand(text string) (string, string, bool) {
 	// Check with prefixes first
 	for _, prefix := range CommandPrefixes {
 		if strings.HasPrefix(text, prefix) {
-			content := strings.TrimPrefix(text, prefix)
-			parts := strings.Fields(content)
-			if len(parts) == 0 {
+			cmd, args := splitCommand(strings.TrimPrefix(text, prefix))
+			if cmd == "" {
 				return "", "", false
 			}
-
-			cmd := strings.ToLower(parts[0])
-			args := ""
-			if len(parts) > 1 {
-				args = strings.TrimSpace(strings.Join(parts[1:], " "))
-			}
 			return cmd, args, true
 		}
 	}
 
 	// No prefix - check if it's a known command
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return "", "", false
-	}
-
-	cmd := strings.ToLower(parts[0])
-	args := ""
-	if len(parts) > 1 {
-		args = strings.TrimSpace(strings.Join(parts[1:], " "))
-	}
+	cmd, args := splitCommand(text)
 
-	// Only allow no-prefix for specific commands (shorter, common ones)
-	allowedNoPrefixCmds := map[string]bool{
-		"bot":        true,
-		"menu":       true,
-		"info":       true,
-		"ping":       true,
-		"status":     true,
-		"online":     true,
-		"typing":     true,
-		"record":     true,
-		"readstory":  true,
-		"likestory":  true,
-		"storydelay": true,
-		"jadibot":    true,
-		"listjadibot": true,
-		"deljadibot": true,
-	}
-
-	if allowedNoPrefixCmds[cmd] {
+	// Only allow no-prefix for commands registered with NoPrefix
+	if DefaultRegistry.allowsNoPrefix(cmd) {
 		return cmd, args, true
 	}
 
 	return "", "", false
 }
 
+// HasCommandPrefix reports whether text starts with one of CommandPrefixes
+func HasCommandPrefix(text string) bool {
+	text = strings.TrimSpace(text)
+	for _, prefix := range CommandPrefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // BuildCommandString creates normalized command string for comparison
 // Handles commands with arguments (e.g., "online on" -> checks both cmd and args)
 func BuildCommandString(cmd, args string) string {
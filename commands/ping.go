@@ -11,10 +11,25 @@ import (
 	waProto "go.mau.fi/whatsmeow/binary/proto"
 	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
+
+	"whatsapp-bot/core"
 )
 
 var BotStartTime = time.Now()
 
+func init() {
+	Register(&Command{
+		Name:        "ping",
+		Category:    CategoryGeneral,
+		Description: "Cek response time",
+		Role:        RolePublic,
+		NoPrefix:    true,
+		Handler: func(ctx *Context) {
+			HandlePingCommand(ctx.Client, ctx.Chat, ctx.MessageID, ctx.Sender)
+		},
+	})
+}
+
 func getSystemInfo() (cpuCount int, goroutines int, totalMB uint64, usedMB uint64, ramPercent float64) {
 	cpuCount = runtime.NumCPU()
 	goroutines = runtime.NumGoroutine()
@@ -57,7 +72,7 @@ func getUptime() string {
 }
 
 func sendPingImage(ctx context.Context, client *whatsmeow.Client, chatJID types.JID, caption string, messageID string, senderJID types.JID) error {
-	imageFile := "img/bot.png"
+	imageFile := core.GetPaths().BotImage()
 	imageData, err := os.ReadFile(imageFile)
 	if err != nil {
 		return err
@@ -133,6 +148,7 @@ func HandlePingCommand(client *whatsmeow.Client, chatJID types.JID, messageID st
 		uptime,
 		time.Now().Format("15:04:05"))
 
+	pingText = tagReply(client, pingText)
 	imgErr := sendPingImage(ctx, client, chatJID, pingText, messageID, senderJID)
 	if imgErr != nil {
 		replyMsg := &waProto.Message{
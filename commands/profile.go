@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "profile",
+		Category:    CategoryFeature,
+		Description: "Simpan/pakai kumpulan setting",
+		Usage:       "list/save/use/diff/del [nama] [--jadibot=62xxx]",
+		Args: []ArgSpec{
+			{Name: "aksi", Type: ArgString, Choices: []string{"list", "save", "use", "diff", "del"}},
+			{Name: "nama", Type: ArgString, Optional: true},
+		},
+		Role:    RoleAdmin,
+		Handler: handleProfileCommand,
+	})
+}
+
+// profileTarget is what a profile is saved from or applied to: the main bot
+// or one jadibot session (--jadibot=62xxx)
+type profileTarget struct {
+	jadibot string
+	// account is the main account the command was sent to
+	account string
+}
+
+func (t profileTarget) label() string {
+	if t.jadibot != "" {
+		return "jadibot " + t.jadibot
+	}
+	if core.MultiAccount() {
+		return "akun " + t.account
+	}
+	return "bot utama"
+}
+
+func (t profileTarget) config() (core.BotConfig, error) {
+	if t.jadibot == "" {
+		return core.AccountConfig(t.account), nil
+	}
+	cfg, ok := features.GetJadibotManager().SessionConfig(t.jadibot)
+	if !ok {
+		return cfg, fmt.Errorf("jadibot %s tidak ditemukan", t.jadibot)
+	}
+	return cfg, nil
+}
+
+// apply applies the profile and returns the config before and after
+func (t profileTarget) apply(patch core.ConfigPatch) (before, after core.BotConfig, err error) {
+	if t.jadibot != "" {
+		return features.GetJadibotManager().UpdateSessionSettings(t.jadibot, patch)
+	}
+	before = core.AccountConfig(t.account)
+	if _, err := core.ApplyAccountPatch(t.account, patch); err != nil {
+		return before, before, err
+	}
+	return before, core.AccountConfig(t.account), nil
+}
+
+// describeChanges lists the fields that differ between before and after,
+// or "" if nothing differs
+func describeChanges(before, after core.BotConfig) string {
+	var text strings.Builder
+	for _, field := range core.ConfigFields() {
+		old, now := field.Get(before), field.Get(after)
+		if old != now {
+			text.WriteString(fmt.Sprintf("%s %s: %s → %s\n", field.Icon, field.Label, field.Format(old), fieldStatus(field, now)))
+		}
+	}
+	return text.String()
+}
+
+func handleProfileCommand(ctx *Context) {
+	target := profileTarget{account: ctx.Account}
+	if raw := ctx.Args.Flag("jadibot"); raw != "" {
+		phone, err := NormalizePhone(raw)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Nomor jadibot tidak valid!*\n\n%v", err))
+			return
+		}
+		if !canManageJadibot(ctx, phone) {
+			return
+		}
+		target.jadibot = phone
+	}
+
+	action := ctx.Args.String("aksi")
+	if action == "list" {
+		handleProfileList(ctx, target)
+		return
+	}
+
+	if !ctx.Args.Has("nama") {
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\nCara pakai:\n*.profile %s <nama>*", action))
+		return
+	}
+	name, err := core.NormalizeProfileName(ctx.Args.String("nama"))
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	switch action {
+	case "save":
+		cfg, err := target.config()
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		existed, err := core.SaveProfile(name, cfg)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Gagal menyimpan profile!*\n\n%v", err))
+			return
+		}
+		verb := "disimpan"
+		if existed {
+			verb = "diperbarui"
+		}
+		ctx.Reply(fmt.Sprintf("✅ Profile *%s* %s dari setting %s", name, verb, target.label()))
+		fmt.Printf("%s✅ Profile %s %s (%s)%s\n", ColorGreen, name, verb, target.label(), ColorReset)
+
+	case "use":
+		patch, ok := core.GetProfile(name)
+		if !ok {
+			ctx.Reply(fmt.Sprintf("❌ Profile *%s* tidak ada. Ketik *.profile list*", name))
+			return
+		}
+		before, after, err := target.apply(patch)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Gagal memakai profile!*\n\n%v", err))
+			return
+		}
+		changes := describeChanges(before, after)
+		if changes == "" {
+			ctx.Reply(fmt.Sprintf("ℹ️ Setting %s sudah sama dengan profile *%s*", target.label(), name))
+			return
+		}
+		ctx.Reply(fmt.Sprintf("✅ Profile *%s* dipakai untuk %s\n\n%s", name, target.label(), strings.TrimSpace(changes)))
+		fmt.Printf("%s✅ Profile %s dipakai (%s)%s\n", ColorGreen, name, target.label(), ColorReset)
+
+	case "diff":
+		patch, ok := core.GetProfile(name)
+		if !ok {
+			ctx.Reply(fmt.Sprintf("❌ Profile *%s* tidak ada. Ketik *.profile list*", name))
+			return
+		}
+		current, err := target.config()
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ %v", err))
+			return
+		}
+		patch.Normalize()
+		changes := describeChanges(current, patch.Apply(current))
+		if changes == "" {
+			ctx.Reply(fmt.Sprintf("ℹ️ Setting %s sama dengan profile *%s*", target.label(), name))
+			return
+		}
+		ctx.Reply(fmt.Sprintf("🔍 *%s → profile %s*\n\n%s", target.label(), name, strings.TrimSpace(changes)))
+
+	case "del":
+		deleted, err := core.DeleteProfile(name)
+		if err != nil {
+			ctx.Reply(fmt.Sprintf("❌ *Gagal menghapus profile!*\n\n%v", err))
+			return
+		}
+		if !deleted {
+			ctx.Reply(fmt.Sprintf("ℹ️ Profile *%s* tidak ada", name))
+			return
+		}
+		ctx.Reply(fmt.Sprintf("✅ Profile *%s* dihapus", name))
+	}
+}
+
+func handleProfileList(ctx *Context, target profileTarget) {
+	current, err := target.config()
+	if err != nil {
+		ctx.Reply(fmt.Sprintf("❌ %v", err))
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString("🗂️ *DAFTAR PROFILE*\n\n")
+	names := core.ProfileNames()
+	if len(names) == 0 {
+		text.WriteString("Belum ada profile.\n")
+	}
+	for _, name := range names {
+		patch, _ := core.GetProfile(name)
+		var parts []string
+		for _, key := range patch.Keys() {
+			field, _ := core.LookupConfigField(key)
+			label := field.Command
+			if label == "" {
+				label = field.Key
+			}
+			parts = append(parts, fmt.Sprintf("%s %s", label, field.Format(patch[key])))
+		}
+
+		marker := "•"
+		if patch.Apply(current) == current {
+			marker = "✅"
+		}
+		text.WriteString(fmt.Sprintf("%s *%s*\n   %s\n", marker, name, strings.Join(parts, ", ")))
+	}
+	text.WriteString(fmt.Sprintf("\n✅ = sesuai setting %s saat ini\n", target.label()))
+	text.WriteString("💡 *.profile use <nama>* untuk memakai")
+	ctx.Reply(text.String())
+}
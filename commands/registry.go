@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+
+	"whatsapp-bot/core"
+)
+
+// Role is the minimum access level needed to run a command
+type Role int
+
+const (
+	RolePublic Role = iota
+	RoleJadibot
+	RoleAdmin
+	RoleOwner
+)
+
+func (r Role) String() string {
+	switch r {
+	case RolePublic:
+		return "public"
+	case RoleJadibot:
+		return "jadibot"
+	case RoleAdmin:
+		return "admin"
+	case RoleOwner:
+		return "owner"
+	}
+	return fmt.Sprintf("role(%d)", int(r))
+}
+
+// Menu categories, in the order they are shown in .menu
+const (
+	CategoryFeature = "⚙️ FITUR"
+	CategoryJadibot = "📱 JADIBOT"
+	CategoryAdmin   = "👑 ADMIN"
+	CategoryGeneral = "ℹ️ COMMAND LAINNYA"
+)
+
+var categoryOrder = []string{CategoryFeature, CategoryJadibot, CategoryAdmin, CategoryGeneral}
+
+// Scope says where a command can be run: on the main bot, inside a jadibot
+// session by the jadibot's own account, or both
+type Scope int
+
+const (
+	ScopeMain Scope = iota
+	ScopeBoth
+	ScopeSession
+)
+
+// Context carries everything a command handler needs about the incoming message
+type Context struct {
+	Client    *whatsmeow.Client
+	Message   *events.Message
+	Chat      types.JID
+	MessageID string
+	Sender    types.JID
+	Command   string
+	// RawArgs is everything typed after the command name
+	RawArgs string
+	// Args holds RawArgs parsed against the command's Args specs
+	Args *Arguments
+	// Prefixed is true when the command was typed with ., !, - or /
+	Prefixed bool
+	// Role is the resolved role of the sender
+	Role Role
+	// SenderPhone is the sender's phone number, resolved from LID if needed
+	SenderPhone string
+	// Session is the phone number of the jadibot session the command was
+	// sent from, or "" on the main bot. Handlers of ScopeBoth commands must
+	// act on that session instead of the main bot.
+	Session string
+	// Account is the main account that received the command, or that the
+	// jadibot session belongs to. Settings and jadibots are per account.
+	Account string
+}
+
+// tagReply marks a main account's reply with the account in multi-account
+// mode, so chats that several accounts are in can tell them apart
+func tagReply(client *whatsmeow.Client, text string) string {
+	if client.Store.ID == nil || !core.IsAccount(client.Store.ID.User) {
+		return text
+	}
+	return text + "\n\n🤖 _Akun: " + client.Store.ID.User + "_"
+}
+
+// Reply sends text as a quoted reply to the message that triggered the command
+func (c *Context) Reply(text string) error {
+	replyMsg := &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text: proto.String(tagReply(c.Client, text)),
+			ContextInfo: &waProto.ContextInfo{
+				StanzaID:    proto.String(c.MessageID),
+				Participant: proto.String(c.Sender.String()),
+			},
+		},
+	}
+	_, err := c.Client.SendMessage(context.Background(), c.Chat, replyMsg)
+	return err
+}
+
+// Command describes a single chat command
+type Command struct {
+	Name        string
+	Aliases     []string
+	Category    string
+	Description string
+	Usage       string
+	Role        Role
+	// Args, if set, is validated before the handler runs and a usage
+	// error is replied automatically
+	Args []ArgSpec
+	// NoPrefix allows the command to be typed without ., !, - or /
+	NoPrefix bool
+	// Scope defaults to ScopeMain. Inside a jadibot session Role is not
+	// checked: the scope alone decides what the session's account may run.
+	Scope Scope
+	// Status, if set, is shown next to the command in .menu (e.g. "✅ ON").
+	// cfg is the config of the main account or jadibot session the menu is
+	// for.
+	Status  func(cfg core.BotConfig) string
+	Handler func(ctx *Context)
+}
+
+// AvailableIn reports whether cmd can run on the main bot (session "") or
+// inside a jadibot session
+func (cmd *Command) AvailableIn(session string) bool {
+	if session == "" {
+		return cmd.Scope != ScopeSession
+	}
+	return cmd.Scope != ScopeMain
+}
+
+// Registry holds all known commands, indexed by name and alias
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]*Command
+	order    []*Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: make(map[string]*Command),
+	}
+}
+
+// DefaultRegistry is where commands register themselves from init()
+var DefaultRegistry = NewRegistry()
+
+// Register adds cmd to the registry. Duplicate names are a programming
+// error and panic at startup.
+func (r *Registry) Register(cmd *Command) {
+	if cmd.Name == "" || cmd.Handler == nil {
+		panic("commands: command must have a name and a handler")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if _, exists := r.commands[name]; exists {
+			panic(fmt.Sprintf("commands: duplicate command %q", name))
+		}
+		r.commands[name] = cmd
+	}
+	r.order = append(r.order, cmd)
+}
+
+// Lookup finds a command by name or alias
+func (r *Registry) Lookup(name string) *Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.commands[strings.ToLower(name)]
+}
+
+// Commands returns all registered commands in registration order
+func (r *Registry) Commands() []*Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmds := make([]*Command, len(r.order))
+	copy(cmds, r.order)
+	return cmds
+}
+
+// ByCategory groups commands by menu category, following categoryOrder
+func (r *Registry) ByCategory() map[string][]*Command {
+	grouped := make(map[string][]*Command)
+	for _, cmd := range r.Commands() {
+		category := cmd.Category
+		if category == "" {
+			category = CategoryGeneral
+		}
+		grouped[category] = append(grouped[category], cmd)
+	}
+	return grouped
+}
+
+// Categories returns the categories that have at least one command, in menu order
+func (r *Registry) Categories() []string {
+	grouped := r.ByCategory()
+
+	var categories []string
+	for _, category := range categoryOrder {
+		if len(grouped[category]) > 0 {
+			categories = append(categories, category)
+		}
+	}
+
+	var extra []string
+	for category := range grouped {
+		known := false
+		for _, c := range categoryOrder {
+			if c == category {
+				known = true
+				break
+			}
+		}
+		if !known {
+			extra = append(extra, category)
+		}
+	}
+	sort.Strings(extra)
+	return append(categories, extra...)
+}
+
+// allowsNoPrefix reports whether name may be used without a prefix
+func (r *Registry) allowsNoPrefix(name string) bool {
+	cmd := r.Lookup(name)
+	return cmd != nil && cmd.NoPrefix
+}
+
+// Dispatch runs the handler for ctx.Command after checking the sender's
+// role. It returns false if the command is unknown.
+func (r *Registry) Dispatch(ctx *Context) bool {
+	cmd := r.Lookup(ctx.Command)
+	if cmd == nil || !cmd.AvailableIn(ctx.Session) {
+		return false
+	}
+
+	// Public users and jadibot accounts must use a prefix so normal chat
+	// isn't treated as commands
+	if (ctx.Role == RolePublic || ctx.Session != "") && !ctx.Prefixed {
+		return false
+	}
+
+	required := EffectiveRole(cmd)
+	if ctx.Session == "" && ctx.Role < required {
+		fmt.Printf("%s⛔ %sCommand .%s ditolak untuk %s (butuh %s)%s\n", ColorYellow, core.AccountTag(ctx.Account), cmd.Name, ctx.Sender.String(), required, ColorReset)
+		if ctx.Role > RolePublic {
+			ctx.Reply(fmt.Sprintf("⛔ *Akses ditolak!*\n\nCommand *.%s* hanya untuk %s.", cmd.Name, roleLabel(required)))
+		}
+		return true
+	}
+
+	args, err := ParseArgs(ctx.RawArgs, cmd.Args)
+	ctx.Args = args
+	if err != nil {
+		usage := "." + cmd.Name
+		if u := UsageFor(cmd); u != "" {
+			usage += " " + u
+		}
+		ctx.Reply(fmt.Sprintf("❌ *Format Salah!*\n\n%v\n\nCara pakai:\n*%s*", err, usage))
+		return true
+	}
+
+	if core.MultiAccount() {
+		fmt.Printf("%s▶️ %s.%s dari %s%s\n", ColorCyan, core.AccountTag(ctx.Account), cmd.Name, ctx.Sender.User, ColorReset)
+	}
+	cmd.Handler(ctx)
+	return true
+}
+
+// Register adds cmd to DefaultRegistry
+func Register(cmd *Command) {
+	DefaultRegistry.Register(cmd)
+}
+
+// Dispatch runs ctx.Command from DefaultRegistry
+func Dispatch(ctx *Context) bool {
+	return DefaultRegistry.Dispatch(ctx)
+}
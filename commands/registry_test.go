@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
+)
+
+// useTempRoles points roles.json at a fresh temp dir for one test. Role
+// edits made through addOwner, addAdmin and setCommandRole are undone
+// afterwards.
+func useTempRoles(t *testing.T) {
+	t.Helper()
+	previous := core.GetPaths()
+	dir := t.TempDir()
+	core.SetPaths(core.NewPaths(dir, filepath.Join(dir, "img")))
+	t.Cleanup(func() { core.SetPaths(previous) })
+}
+
+func addOwner(t *testing.T, id string) {
+	t.Helper()
+	if _, err := core.AddOwner(id); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { core.RemoveOwner(id) })
+}
+
+func addAdmin(t *testing.T, id string) {
+	t.Helper()
+	if _, err := core.AddAdmin(id); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { core.RemoveAdmin(id) })
+}
+
+func setCommandRole(t *testing.T, command, role string) {
+	t.Helper()
+	if err := core.SetCommandRole(command, role); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { core.SetCommandRole(command, "") })
+}
+
+// testRegistry has one command per role and scope; ran records which
+// handler ran last
+func testRegistry(ran *string) *Registry {
+	r := NewRegistry()
+	for _, cmd := range []*Command{
+		{Name: "publik", Role: RolePublic},
+		{Name: "khususadmin", Role: RoleAdmin},
+		{Name: "khususowner", Role: RoleOwner},
+		{Name: "keduanya", Role: RoleAdmin, Scope: ScopeBoth},
+		{Name: "sesi", Role: RolePublic, Scope: ScopeSession},
+	} {
+		name := cmd.Name
+		cmd.Handler = func(ctx *Context) { *ran = name }
+		r.Register(cmd)
+	}
+	return r
+}
+
+func TestDispatchRoleGate(t *testing.T) {
+	useTempRoles(t)
+
+	// Denials are only tested for public senders, which get no reply (the
+	// tests have no connected client to reply with)
+	tests := []struct {
+		name      string
+		ctx       Context
+		wantFound bool
+		wantRan   bool
+	}{
+		{name: "publik", ctx: Context{Command: "publik", Role: RolePublic, Prefixed: true}, wantFound: true, wantRan: true},
+		{name: "publik", ctx: Context{Command: "publik", Role: RolePublic}, wantFound: false},
+		{name: "publik", ctx: Context{Command: "publik", Role: RoleAdmin}, wantFound: true, wantRan: true},
+		{name: "khususadmin", ctx: Context{Command: "khususadmin", Role: RolePublic, Prefixed: true}, wantFound: true, wantRan: false},
+		{name: "khususadmin", ctx: Context{Command: "khususadmin", Role: RoleAdmin, Prefixed: true}, wantFound: true, wantRan: true},
+		{name: "khususowner", ctx: Context{Command: "KhususOwner", Role: RoleOwner}, wantFound: true, wantRan: true},
+		{name: "tidakada", ctx: Context{Command: "tidakada", Role: RoleOwner, Prefixed: true}, wantFound: false},
+		// Jadibot sessions skip the role check; the scope decides
+		{name: "keduanya", ctx: Context{Command: "keduanya", Role: RolePublic, Prefixed: true, Session: "6281234567890"}, wantFound: true, wantRan: true},
+		{name: "sesi", ctx: Context{Command: "sesi", Role: RolePublic, Prefixed: true, Session: "6281234567890"}, wantFound: true, wantRan: true},
+		{name: "khususowner", ctx: Context{Command: "khususowner", Role: RoleOwner, Prefixed: true, Session: "6281234567890"}, wantFound: false},
+		{name: "keduanya", ctx: Context{Command: "keduanya", Role: RoleOwner, Session: "6281234567890"}, wantFound: false},
+		{name: "sesi", ctx: Context{Command: "sesi", Role: RoleOwner, Prefixed: true}, wantFound: false},
+	}
+
+	for _, tt := range tests {
+		var ran string
+		r := testRegistry(&ran)
+		ctx := tt.ctx
+		found := r.Dispatch(&ctx)
+		if found != tt.wantFound || (ran == tt.name) != tt.wantRan {
+			t.Errorf("Dispatch(%+v) = %v, ran %q; want %v, ran=%v", tt.ctx, found, ran, tt.wantFound, tt.wantRan)
+		}
+	}
+}
+
+func TestEffectiveRole(t *testing.T) {
+	useTempRoles(t)
+
+	var ran string
+	r := testRegistry(&ran)
+	admin, owner := r.Lookup("khususadmin"), r.Lookup("khususowner")
+
+	if got := EffectiveRole(admin); got != RoleAdmin {
+		t.Errorf("EffectiveRole(admin command) = %v, want admin", got)
+	}
+
+	setCommandRole(t, "khususadmin", "public")
+	if got := EffectiveRole(admin); got != RolePublic {
+		t.Errorf("EffectiveRole after .cmdrole public = %v, want public", got)
+	}
+	ctx := Context{Command: "khususadmin", Role: RolePublic, Prefixed: true}
+	if !r.Dispatch(&ctx) || ran != "khususadmin" {
+		t.Errorf("lowered command did not run for a public sender")
+	}
+
+	// A stored override can never open an owner command up
+	setCommandRole(t, "khususowner", "public")
+	if got := EffectiveRole(owner); got != RoleOwner {
+		t.Errorf("EffectiveRole(owner command) with stored public = %v, want owner", got)
+	}
+	ran = ""
+	ctx = Context{Command: "khususowner", Role: RolePublic, Prefixed: true}
+	if !r.Dispatch(&ctx) || ran != "" {
+		t.Errorf("owner command ran for a public sender")
+	}
+}
+
+func TestResolveRole(t *testing.T) {
+	useTempRoles(t)
+
+	bot := types.NewJID("6280000000001", types.DefaultUserServer)
+	client := &whatsmeow.Client{Store: &store.Device{ID: &bot}}
+	group := types.NewJID("120363000000000000", types.GroupServer)
+
+	owner := types.NewJID("6281111111111", types.DefaultUserServer)
+	both := types.NewJID("6282222222222", types.DefaultUserServer)
+	admin := types.NewJID("6283333333333", types.DefaultUserServer)
+	stranger := types.NewJID("6284444444444", types.DefaultUserServer)
+	lidAdmin := types.NewJID("100000000000001", types.HiddenUserServer)
+	lidOwner := types.NewJID("100000000000002", types.HiddenUserServer)
+
+	addOwner(t, owner.User)
+	addOwner(t, both.User)
+	addAdmin(t, both.User)
+	addAdmin(t, admin.User)
+	addAdmin(t, lidAdmin.String())
+	utils.StoreLIDMapping(lidOwner.String(), owner.String())
+
+	message := func(chat, sender types.JID, fromMe bool) *events.Message {
+		return &events.Message{Info: types.MessageInfo{MessageSource: types.MessageSource{
+			Chat: chat, Sender: sender, IsFromMe: fromMe,
+		}}}
+	}
+
+	tests := []struct {
+		name      string
+		msg       *events.Message
+		want      Role
+		wantPhone string
+	}{
+		{"sent by the bot itself", message(group, stranger, true), RoleOwner, stranger.User},
+		{"bot account as sender", message(group, bot, false), RoleOwner, bot.User},
+		{"bot's own chat", message(bot, stranger, false), RoleOwner, stranger.User},
+		{"owner", message(group, owner, false), RoleOwner, owner.User},
+		{"owner beats admin", message(group, both, false), RoleOwner, both.User},
+		{"admin", message(group, admin, false), RoleAdmin, admin.User},
+		{"admin stored by LID", message(group, lidAdmin, false), RoleAdmin, ""},
+		{"LID resolved to an owner's number", message(group, lidOwner, false), RoleOwner, owner.User},
+		{"anyone else", message(group, stranger, false), RolePublic, stranger.User},
+	}
+
+	for _, tt := range tests {
+		role, phone := ResolveRole(client, tt.msg)
+		if role != tt.want || phone != tt.wantPhone {
+			t.Errorf("%s: ResolveRole = %v, %q; want %v, %q", tt.name, role, phone, tt.want, tt.wantPhone)
+		}
+	}
+}
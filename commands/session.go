@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+	"whatsapp-bot/utils"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "stop",
+		Category:    CategoryJadibot,
+		Description: "Berhenti jadi bot (hapus session)",
+		Scope:       ScopeSession,
+		Handler:     handleStopCommand,
+	})
+
+	Register(&Command{
+		Name:        "sessions",
+		Category:    CategoryAdmin,
+		Description: "Status koneksi semua akun",
+		Role:        RoleAdmin,
+		Handler:     handleSessionsCommand,
+	})
+}
+
+// configFor returns the config commands should show or change: the main
+// account's, or a jadibot session's own
+func configFor(ctx *Context) core.BotConfig {
+	if ctx.Session == "" {
+		return core.AccountConfig(ctx.Account)
+	}
+	cfg, _ := features.GetJadibotManager().SessionConfig(ctx.Session)
+	return cfg
+}
+
+// isOwnChat reports whether chat is the account's chat with itself
+// ("Message yourself"), by phone number or LID
+func isOwnChat(client *whatsmeow.Client, chat types.JID) bool {
+	if client.Store.ID == nil {
+		return false
+	}
+	return chat.User == client.Store.ID.User ||
+		(!client.Store.LID.IsEmpty() && chat.User == client.Store.LID.User)
+}
+
+// HandleMessage routes a non-story message received by any account the
+// SessionManager runs: the primary account runs the full pipeline, jadibot
+// accounts only commands sent to themselves
+func HandleMessage(session *features.ManagedSession, msg *events.Message) {
+	if !session.Primary {
+		HandleSessionMessage(session.ID, session.Account, session.Client, msg)
+		return
+	}
+
+	client := session.Client
+	features.HandleAutoPresence(client, msg)
+
+	messageText := messageText(msg)
+	if utils.IsGroupJID(msg.Info.Chat.String()) {
+		utils.CacheGroupParticipantMappings(client, msg.Info.Chat)
+	}
+
+	cmd, args, isCmd := ParseCommand(messageText)
+	if !isCmd {
+		return
+	}
+	role, senderPhone := ResolveRole(client, msg)
+	Dispatch(&Context{
+		Client:      client,
+		Message:     msg,
+		Chat:        msg.Info.Chat,
+		MessageID:   msg.Info.ID,
+		Sender:      msg.Info.Sender,
+		Command:     cmd,
+		RawArgs:     args,
+		Prefixed:    HasCommandPrefix(messageText),
+		Role:        role,
+		SenderPhone: senderPhone,
+		Account:     session.ID,
+	})
+}
+
+// messageText returns the text of a plain or extended text message
+func messageText(msg *events.Message) string {
+	if msg.Message.GetConversation() != "" {
+		return msg.Message.GetConversation()
+	}
+	return msg.Message.GetExtendedTextMessage().GetText()
+}
+
+// HandleSessionMessage runs commands a jadibot account sends to itself.
+// Only messages the account sends in its own chat are considered, so it
+// can't be triggered by anyone else and replies never reach other chats.
+// The sender is a jadibot user and only ScopeBoth/ScopeSession commands
+// are available.
+func HandleSessionMessage(session, account string, client *whatsmeow.Client, msg *events.Message) {
+	if !msg.Info.IsFromMe || !isOwnChat(client, msg.Info.Chat) {
+		return
+	}
+
+	messageText := messageText(msg)
+	cmd, args, isCmd := ParseCommand(messageText)
+	if !isCmd {
+		return
+	}
+	if Dispatch(&Context{
+		Client:      client,
+		Message:     msg,
+		Chat:        msg.Info.Chat,
+		MessageID:   msg.Info.ID,
+		Sender:      msg.Info.Sender,
+		Command:     cmd,
+		RawArgs:     args,
+		Prefixed:    HasCommandPrefix(messageText),
+		Role:        RoleJadibot,
+		SenderPhone: session,
+		Session:     session,
+		Account:     account,
+	}) {
+		fmt.Printf("%s🤖 %sJadibot %s menjalankan .%s%s\n", ColorCyan, core.AccountTag(account), session, cmd, ColorReset)
+	}
+}
+
+// handleSessionsCommand lists every account the SessionManager runs, the
+// main bot first, with its connection metrics
+func handleSessionsCommand(ctx *Context) {
+	statuses := features.GetSessionManager().All()
+
+	var text strings.Builder
+	text.WriteString("🔌 *SESSION AKTIF*\n\n")
+	if len(statuses) == 0 {
+		text.WriteString("Belum ada session yang berjalan.\n")
+	}
+	for _, s := range statuses {
+		kind := "🤖 Jadibot"
+		if s.Primary {
+			kind = "👑 Utama"
+		}
+		m := s.Metrics
+		text.WriteString(fmt.Sprintf("%s *%s* - %s\n", kind, s.ID, s.State.Label()))
+		if !s.Primary && core.MultiAccount() {
+			text.WriteString(fmt.Sprintf("   Akun: %s\n", s.Account))
+		}
+		text.WriteString(fmt.Sprintf("   Terhubung %dx, terputus %dx, reconnect %dx\n", m.Connects, m.Disconnects, m.ReconnectAttempts))
+		text.WriteString(fmt.Sprintf("   Gagal health check %d (beruntun %d), pesan %d\n", m.HealthFailures, s.FailCount, m.Messages))
+		if !m.LastDisconnected.IsZero() {
+			text.WriteString(fmt.Sprintf("   Terakhir terputus: %s\n", features.FormatStartDateTime(m.LastDisconnected)))
+		}
+		text.WriteString("\n")
+	}
+	text.WriteString(fmt.Sprintf("📊 Total: %d session", len(statuses)))
+	ctx.Reply(text.String())
+}
+
+func handleStopCommand(ctx *Context) {
+	ctx.Reply("👋 *Jadibot dihentikan.*\n\nSession kamu dihapus dari bot. Ketik *.jadibot <nomor>* ke bot utama untuk daftar lagi.")
+
+	// Removing disconnects this client, so let the reply go out and the
+	// event handler return first
+	session := ctx.Session
+	go func() {
+		time.Sleep(2 * time.Second)
+		if err := features.GetJadibotManager().RemoveSession(session); err != nil {
+			fmt.Printf("%s⚠️ Gagal menghentikan jadibot %s: %v%s\n", ColorYellow, session, err, ColorReset)
+		}
+	}()
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+)
+
+func init() {
+	Register(&Command{
+		Name:        "status",
+		Category:    CategoryGeneral,
+		Description: "Lihat status fitur",
+		Role:        RoleAdmin,
+		NoPrefix:    true,
+		Scope:       ScopeBoth,
+		Handler:     handleStatusCommand,
+	})
+}
+
+func handleStatusCommand(ctx *Context) {
+	config := configFor(ctx)
+	fields := core.ConfigFields()
+	if ctx.Session != "" {
+		fields = core.SessionFields()
+	}
+
+	var statusText strings.Builder
+	statusText.WriteString("📊 STATUS FITUR:\n")
+	for _, field := range fields {
+		value := field.Get(config)
+		status := field.Format(value)
+		if b, ok := value.(bool); ok {
+			if b {
+				status += " ✅"
+			} else {
+				status += " ❌"
+			}
+		}
+		statusText.WriteString(fmt.Sprintf("\n%s %s: %s", field.Icon, field.Label, status))
+	}
+
+	session := ctx.Session
+	if session == "" {
+		session = ctx.Account
+	}
+	statusText.WriteString(connectionStatus(session))
+
+	ctx.Reply(statusText.String())
+	fmt.Printf("%s📊 Status checked%s\n", ColorCyan, ColorReset)
+}
+
+// connectionStatus describes the reconnect state of a main account or of a
+// jadibot session, or returns "" if it isn't managed
+func connectionStatus(session string) string {
+	s, ok := features.GetSessionManager().Status(session)
+	if !ok {
+		return ""
+	}
+
+	var text strings.Builder
+	text.WriteString("\n\n🔌 KONEKSI:")
+	text.WriteString(fmt.Sprintf("\n📶 Status: %s", s.State.Label()))
+	text.WriteString(fmt.Sprintf("\n❗ Gagal beruntun: %d", s.AttemptFailures))
+	if !s.LastFailTime.IsZero() {
+		text.WriteString(fmt.Sprintf("\n🕒 Gagal terakhir: %s (%s lalu)", features.FormatStartDateTime(s.LastFailTime), features.FormatDuration(time.Since(s.LastFailTime))))
+	}
+	if s.LastError != "" {
+		text.WriteString(fmt.Sprintf("\n⚠️ Error terakhir: %s", s.LastError))
+	}
+	if time.Now().Before(s.BannedUntil) {
+		text.WriteString(fmt.Sprintf("\n⛔ Diblokir sementara sampai %s, otomatisasi dihentikan", features.FormatStartDateTime(s.BannedUntil)))
+	} else if s.CircuitOpen() {
+		text.WriteString(fmt.Sprintf("\n🔴 Circuit terbuka sampai %s", features.FormatStartDateTime(s.CircuitUntil)))
+	} else if !s.NextRetry.IsZero() {
+		text.WriteString(fmt.Sprintf("\n🔄 Percobaan berikutnya: %s", features.FormatStartDateTime(s.NextRetry)))
+	}
+	return text.String()
+}
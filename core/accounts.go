@@ -0,0 +1,188 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"whatsapp-bot/utils"
+)
+
+// In multi-account mode one process runs several main bot accounts. Each
+// keeps its own settings in <data>/bossbot/<nomor>.json, a ConfigPatch on
+// top of settings.dat like a jadibot session's settings; roles, overrides
+// and profiles stay shared. In single-account mode no account is
+// registered and every function here falls back to the global config.
+var (
+	accountOrder    []string
+	accountSettings = make(map[string]ConfigPatch)
+	accountMutex    sync.RWMutex
+)
+
+// InitAccounts registers the main accounts of a multi-account run, in
+// order, and loads their own settings. A missing file means the account
+// follows settings.dat; a broken one is an error, as for settings.dat.
+func InitAccounts(numbers []string) error {
+	accountMutex.Lock()
+	defer accountMutex.Unlock()
+
+	for _, number := range numbers {
+		if _, exists := accountSettings[number]; exists {
+			continue
+		}
+		settings, err := loadAccountSettings(number)
+		if err != nil {
+			return err
+		}
+		accountOrder = append(accountOrder, number)
+		accountSettings[number] = settings
+	}
+	return nil
+}
+
+func loadAccountSettings(number string) (ConfigPatch, error) {
+	path := GetPaths().AccountSettings(number)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ConfigPatch{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca %s: %v", path, err)
+	}
+
+	var settings ConfigPatch
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("file %s rusak (bukan JSON yang valid): %v\nPulihkan dari %s.bak atau hapus file tersebut agar akun %s mengikuti settings.dat", path, err, path, number)
+	}
+	if settings == nil {
+		settings = ConfigPatch{}
+	}
+	if err := settings.Normalize(); err != nil {
+		return nil, fmt.Errorf("file %s berisi nilai yang tidak valid: %v", path, err)
+	}
+	return settings, nil
+}
+
+func saveAccountSettings(number string, settings ConfigPatch) error {
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(GetPaths().AccountSettings(number), data, 0o644)
+}
+
+// MultiAccount reports whether this process runs in multi-account mode
+func MultiAccount() bool {
+	accountMutex.RLock()
+	defer accountMutex.RUnlock()
+	return len(accountOrder) > 0
+}
+
+// Accounts returns the registered main accounts in start order
+func Accounts() []string {
+	accountMutex.RLock()
+	defer accountMutex.RUnlock()
+	return append([]string(nil), accountOrder...)
+}
+
+// DefaultAccount is the first registered account, which owns jadibots
+// created before multi-account mode; "" in single-account mode
+func DefaultAccount() string {
+	accountMutex.RLock()
+	defer accountMutex.RUnlock()
+	if len(accountOrder) == 0 {
+		return ""
+	}
+	return accountOrder[0]
+}
+
+// IsAccount reports whether number is a registered main account
+func IsAccount(number string) bool {
+	accountMutex.RLock()
+	defer accountMutex.RUnlock()
+	_, ok := accountSettings[number]
+	return ok
+}
+
+// AccountConfig returns the config of a main account: settings.dat with
+// the account's own settings on top, or just settings.dat if account has
+// none (single-account mode, or "")
+func AccountConfig(account string) BotConfig {
+	accountMutex.RLock()
+	settings := accountSettings[account]
+	accountMutex.RUnlock()
+	return settings.Apply(GetConfig())
+}
+
+// AccountTag prefixes log lines with the account they belong to in
+// multi-account mode; it is "" otherwise
+func AccountTag(account string) string {
+	if account == "" || !MultiAccount() {
+		return ""
+	}
+	return "[" + account + "] "
+}
+
+// SetAccountValue is SetConfigValue for one main account: it stores the
+// value in the account's own settings, or in settings.dat if the account
+// has none
+func SetAccountValue(account, key string, value interface{}) (ConfigField, interface{}, error) {
+	field, ok := LookupConfigField(key)
+	if !ok {
+		return ConfigField{}, nil, fmt.Errorf("setting %q tidak dikenal", key)
+	}
+	if err := field.Validate(value); err != nil {
+		return field, nil, err
+	}
+	changes, err := ApplyAccountPatch(account, ConfigPatch{field.Key: value})
+	if err != nil {
+		return field, nil, err
+	}
+	if len(changes) == 0 {
+		return field, value, nil
+	}
+	return field, changes[0].Old, nil
+}
+
+// ApplyAccountPatch is ApplyConfigPatch for one main account: it merges
+// patch into the account's own settings, or into settings.dat if the
+// account has none
+func ApplyAccountPatch(account string, patch ConfigPatch) ([]ConfigChange, error) {
+	accountMutex.Lock()
+	if _, ok := accountSettings[account]; !ok {
+		accountMutex.Unlock()
+		return ApplyConfigPatch(patch)
+	}
+	if err := patch.Normalize(); err != nil {
+		accountMutex.Unlock()
+		return nil, err
+	}
+
+	previous := accountSettings[account]
+	updated := make(ConfigPatch, len(previous)+len(patch))
+	for k, v := range previous {
+		updated[k] = v
+	}
+	for k, v := range patch {
+		updated[k] = v
+	}
+
+	base := GetConfig()
+	before, after := previous.Apply(base), updated.Apply(base)
+	if err := saveAccountSettings(account, updated); err != nil {
+		accountMutex.Unlock()
+		return nil, fmt.Errorf("gagal menyimpan setting akun %s: %v", account, err)
+	}
+	accountSettings[account] = updated
+	accountMutex.Unlock()
+
+	var changes []ConfigChange
+	for _, field := range configFields {
+		if change := (ConfigChange{Field: field, Old: field.Get(before), New: field.Get(after), Account: account}); change.Old != change.New {
+			changes = append(changes, change)
+			notifyConfigChange(change)
+		}
+	}
+	return changes, nil
+}
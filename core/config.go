@@ -2,21 +2,57 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"sync"
+
+	"whatsapp-bot/utils"
 )
 
+// BotConfig is the runtime configuration. Every field tagged here is
+// controllable from chat with .set/.get; see ConfigField for the tags.
 type BotConfig struct {
-	AutoOnline       bool `json:"auto_online"`
-	AutoTyping       bool `json:"auto_typing"`
-	AutoRecording    bool `json:"auto_recording"`
-	AutoReadStory    bool `json:"auto_read_story"`
-	AutoLikeStory    bool `json:"auto_like_story"`
-	StoryRandomDelay bool `json:"story_random_delay"`
+	AutoOnline       bool `json:"auto_online" session:"true" label:"Auto Online" cmd:"online" icon:"🌐" desc:"Selalu tampil online"`
+	AutoTyping       bool `json:"auto_typing" override:"true" label:"Auto Typing" cmd:"typing" icon:"🖊️" desc:"Tampil mengetik saat ada chat masuk"`
+	AutoRecording    bool `json:"auto_recording" override:"true" label:"Auto Recording" cmd:"record" icon:"🎤" desc:"Tampil merekam audio saat ada chat masuk"`
+	AutoReadStory    bool `json:"auto_read_story" override:"true" session:"true" label:"Auto Read Story" cmd:"readstory" icon:"👁️" desc:"Otomatis lihat story"`
+	AutoLikeStory    bool `json:"auto_like_story" override:"true" session:"true" label:"Auto Like Story" cmd:"likestory" icon:"❤️" desc:"Otomatis react story dengan emoji random"`
+	StoryRandomDelay bool `json:"story_random_delay" override:"true" session:"true" label:"Story Random Delay" cmd:"storydelay" icon:"⏱️" on:"Random (1-20s)" off:"Normal (1s)" desc:"Delay acak sebelum lihat story"`
 }
 
 var (
-	currentConfig = BotConfig{
+	currentConfig = defaultConfig()
+	configMutex   sync.RWMutex
+)
+
+// configVersion is the current settings.dat schema version. Bump it and
+// append a migration to configMigrations when the file layout changes.
+const configVersion = 1
+
+// configFile is the on-disk layout of settings.dat
+type configFile struct {
+	Version int       `json:"version"`
+	Config  BotConfig `json:"config"`
+}
+
+// configMigrations[i] upgrades a raw settings document from version i to
+// version i+1
+var configMigrations = []func(doc map[string]interface{}) error{
+	// v0 -> v1: the legacy file was a flat object of settings without a
+	// version; settings now live under "config"
+	func(doc map[string]interface{}) error {
+		settings := make(map[string]interface{})
+		for key, value := range doc {
+			settings[key] = value
+			delete(doc, key)
+		}
+		doc["config"] = settings
+		return nil
+	},
+}
+
+func defaultConfig() BotConfig {
+	return BotConfig{
 		AutoOnline:       true,
 		AutoTyping:       false,
 		AutoRecording:    false,
@@ -24,76 +60,95 @@ var (
 		AutoLikeStory:    true,
 		StoryRandomDelay: true,
 	}
-	configMutex sync.RWMutex
-	stateFile   = "Wilykun/settings.dat"
-)
+}
 
-var (
-	AutoTypingEnabled    func() bool
-	AutoRecordingEnabled func() bool
-	AutoReadStoryFunc    func() bool
-	AutoLikeStoryFunc    func() bool
-	StoryRandomDelayFunc func() bool
-
-	SetAutoTypingEnabled    func(bool)
-	SetAutoRecordingEnabled func(bool)
-	SetAutoReadStory        func(bool)
-	SetAutoLikeStory        func(bool)
-	SetStoryRandomDelay     func(bool)
-)
+// migrateConfig upgrades doc in place to configVersion and reports the
+// version it started at
+func migrateConfig(doc map[string]interface{}) (int, error) {
+	version := 0
+	if raw, ok := doc["version"]; ok {
+		v, ok := raw.(float64)
+		if !ok || v < 0 || v != float64(int(v)) {
+			return 0, fmt.Errorf("field version tidak valid: %v", raw)
+		}
+		version = int(v)
+		delete(doc, "version")
+	}
+
+	if version > configVersion {
+		return version, fmt.Errorf("versi %d lebih baru dari yang didukung bot ini (%d)", version, configVersion)
+	}
 
-func InitConfig() {
+	for v := version; v < configVersion; v++ {
+		if err := configMigrations[v](doc); err != nil {
+			return version, fmt.Errorf("migrasi v%d -> v%d gagal: %v", v, v+1, err)
+		}
+	}
+	doc["version"] = configVersion
+	return version, nil
+}
+
+// InitConfig loads settings.dat. A missing file starts from defaults; a
+// file that can't be read, parsed or migrated is an error so a broken file
+// is never silently replaced by defaults.
+func InitConfig() error {
 	configMutex.Lock()
 	defer configMutex.Unlock()
 
-	data, err := os.ReadFile(stateFile)
-	if err == nil {
-		var loaded map[string]interface{}
-		if json.Unmarshal(data, &loaded) == nil {
-			if val, ok := loaded["auto_online"].(bool); ok {
-				currentConfig.AutoOnline = val
-			}
-			if val, ok := loaded["auto_typing"].(bool); ok {
-				currentConfig.AutoTyping = val
-			}
-			if val, ok := loaded["auto_recording"].(bool); ok {
-				currentConfig.AutoRecording = val
-			}
-			if val, ok := loaded["auto_read_story"].(bool); ok {
-				currentConfig.AutoReadStory = val
-			}
-			if val, ok := loaded["auto_like_story"].(bool); ok {
-				currentConfig.AutoLikeStory = val
-			}
-			if val, ok := loaded["story_random_delay"].(bool); ok {
-				currentConfig.StoryRandomDelay = val
-			}
-		}
+	path := GetPaths().Settings()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		currentConfig = defaultConfig()
+		return saveState(currentConfig)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", path, err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("file %s rusak (bukan JSON yang valid): %v\nPulihkan dari %s.bak atau hapus file tersebut untuk memakai setting default", path, err, path)
 	}
 
-	if SetAutoTypingEnabled != nil {
-		SetAutoTypingEnabled(currentConfig.AutoTyping)
+	fromVersion, err := migrateConfig(doc)
+	if err != nil {
+		return fmt.Errorf("file %s tidak bisa dimuat: %v", path, err)
 	}
-	if SetAutoRecordingEnabled != nil {
-		SetAutoRecordingEnabled(currentConfig.AutoRecording)
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("file %s tidak bisa dimuat: %v", path, err)
 	}
-	if SetAutoReadStory != nil {
-		SetAutoReadStory(currentConfig.AutoReadStory)
+
+	loaded := configFile{Config: defaultConfig()}
+	if err := json.Unmarshal(migrated, &loaded); err != nil {
+		return fmt.Errorf("file %s berisi nilai yang tidak valid: %v\nPulihkan dari %s.bak atau perbaiki nilainya", path, err, path)
 	}
-	if SetAutoLikeStory != nil {
-		SetAutoLikeStory(currentConfig.AutoLikeStory)
+
+	for _, field := range configFields {
+		if err := field.Validate(field.Get(loaded.Config)); err != nil {
+			return fmt.Errorf("file %s berisi nilai yang tidak valid: %v", path, err)
+		}
 	}
-	if SetStoryRandomDelay != nil {
-		SetStoryRandomDelay(currentConfig.StoryRandomDelay)
+
+	currentConfig = loaded.Config
+
+	if fromVersion != configVersion {
+		if err := saveState(currentConfig); err != nil {
+			return fmt.Errorf("gagal menyimpan hasil migrasi %s: %v", path, err)
+		}
+		fmt.Printf("🔄 %s dimigrasi dari versi %d ke %d (backup: %s.bak)\n", path, fromVersion, configVersion, path)
 	}
+	return nil
 }
 
-func saveState() {
-	data, err := json.Marshal(currentConfig)
+// saveState writes cfg to settings.dat atomically, keeping a .bak copy
+func saveState(cfg BotConfig) error {
+	data, err := json.MarshalIndent(configFile{Version: configVersion, Config: cfg}, "", "  ")
 	if err != nil {
-		return
+		return err
 	}
-	os.WriteFile(stateFile, data, 0644)
+	return utils.WriteFileAtomic(GetPaths().Settings(), data, 0o644)
 }
 
 func GetConfig() BotConfig {
@@ -102,44 +157,3 @@ func GetConfig() BotConfig {
 
 	return currentConfig
 }
-
-func UpdateConfig(autoOnline, autoTyping, autoRecording, autoReadStory, autoLikeStory, storyRandomDelay *bool) {
-	configMutex.Lock()
-	defer configMutex.Unlock()
-
-	if autoOnline != nil {
-		currentConfig.AutoOnline = *autoOnline
-	}
-	if autoTyping != nil {
-		currentConfig.AutoTyping = *autoTyping
-		if SetAutoTypingEnabled != nil {
-			SetAutoTypingEnabled(*autoTyping)
-		}
-	}
-	if autoRecording != nil {
-		currentConfig.AutoRecording = *autoRecording
-		if SetAutoRecordingEnabled != nil {
-			SetAutoRecordingEnabled(*autoRecording)
-		}
-	}
-	if autoReadStory != nil {
-		currentConfig.AutoReadStory = *autoReadStory
-		if SetAutoReadStory != nil {
-			SetAutoReadStory(*autoReadStory)
-		}
-	}
-	if autoLikeStory != nil {
-		currentConfig.AutoLikeStory = *autoLikeStory
-		if SetAutoLikeStory != nil {
-			SetAutoLikeStory(*autoLikeStory)
-		}
-	}
-	if storyRandomDelay != nil {
-		currentConfig.StoryRandomDelay = *storyRandomDelay
-		if SetStoryRandomDelay != nil {
-			SetStoryRandomDelay(*storyRandomDelay)
-		}
-	}
-
-	saveState()
-}
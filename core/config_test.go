@@ -0,0 +1,235 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useTempPaths points the data layout at a fresh temp dir for one test and
+// restores the layout and loaded config afterwards
+func useTempPaths(t *testing.T) Paths {
+	t.Helper()
+	previousPaths, previousConfig := GetPaths(), GetConfig()
+	dir := t.TempDir()
+	p := NewPaths(dir, filepath.Join(dir, "img"))
+	SetPaths(p)
+	t.Cleanup(func() {
+		SetPaths(previousPaths)
+		configMutex.Lock()
+		currentConfig = previousConfig
+		configMutex.Unlock()
+	})
+	return p
+}
+
+func writeSettings(t *testing.T, p Paths, content string) {
+	t.Helper()
+	if err := os.WriteFile(p.Settings(), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readSettings(t *testing.T, p Paths) configFile {
+	t.Helper()
+	data, err := os.ReadFile(p.Settings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file configFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("settings.dat is not valid JSON: %v\n%s", err, data)
+	}
+	return file
+}
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         string
+		wantFrom    int
+		wantConfig  map[string]interface{}
+		wantErrPart string
+	}{
+		{
+			name:       "legacy flat file",
+			doc:        `{"auto_online": false, "auto_typing": true}`,
+			wantFrom:   0,
+			wantConfig: map[string]interface{}{"auto_online": false, "auto_typing": true},
+		},
+		{
+			name:       "explicit version 0",
+			doc:        `{"version": 0, "auto_online": false}`,
+			wantFrom:   0,
+			wantConfig: map[string]interface{}{"auto_online": false},
+		},
+		{
+			name:       "current version is left alone",
+			doc:        `{"version": 1, "config": {"auto_recording": true}}`,
+			wantFrom:   1,
+			wantConfig: map[string]interface{}{"auto_recording": true},
+		},
+		{
+			name:        "future version",
+			doc:         `{"version": 2, "config": {}}`,
+			wantFrom:    2,
+			wantErrPart: "lebih baru",
+		},
+		{
+			name:        "version is not a number",
+			doc:         `{"version": "1", "config": {}}`,
+			wantErrPart: "version tidak valid",
+		},
+		{
+			name:        "fractional version",
+			doc:         `{"version": 1.5, "config": {}}`,
+			wantErrPart: "version tidak valid",
+		},
+		{
+			name:        "negative version",
+			doc:         `{"version": -1, "config": {}}`,
+			wantErrPart: "version tidak valid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+
+			from, err := migrateConfig(doc)
+			if tt.wantErrPart != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrPart) {
+					t.Fatalf("migrateConfig error = %v, want it to mention %q", err, tt.wantErrPart)
+				}
+				if from != tt.wantFrom {
+					t.Errorf("from = %d, want %d", from, tt.wantFrom)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("migrateConfig error: %v", err)
+			}
+			if from != tt.wantFrom {
+				t.Errorf("from = %d, want %d", from, tt.wantFrom)
+			}
+			if doc["version"] != configVersion {
+				t.Errorf("version = %v, want %d", doc["version"], configVersion)
+			}
+			if !reflect.DeepEqual(doc["config"], tt.wantConfig) {
+				t.Errorf("config = %v, want %v", doc["config"], tt.wantConfig)
+			}
+		})
+	}
+}
+
+func TestInitConfigMissingFile(t *testing.T) {
+	p := useTempPaths(t)
+
+	if err := InitConfig(); err != nil {
+		t.Fatalf("InitConfig: %v", err)
+	}
+	if got := GetConfig(); got != defaultConfig() {
+		t.Errorf("config = %+v, want defaults", got)
+	}
+	file := readSettings(t, p)
+	if file.Version != configVersion || file.Config != defaultConfig() {
+		t.Errorf("settings.dat = %+v, want defaults at version %d", file, configVersion)
+	}
+}
+
+func TestInitConfigMigratesLegacyFile(t *testing.T) {
+	p := useTempPaths(t)
+	legacy := `{"auto_online": false, "auto_recording": true}`
+	writeSettings(t, p, legacy)
+
+	if err := InitConfig(); err != nil {
+		t.Fatalf("InitConfig: %v", err)
+	}
+
+	want := defaultConfig()
+	want.AutoOnline = false
+	want.AutoRecording = true
+	if got := GetConfig(); got != want {
+		t.Errorf("config = %+v, want %+v", got, want)
+	}
+
+	file := readSettings(t, p)
+	if file.Version != configVersion || file.Config != want {
+		t.Errorf("rewritten settings.dat = %+v, want %+v at version %d", file, want, configVersion)
+	}
+	backup, err := os.ReadFile(p.Settings() + ".bak")
+	if err != nil {
+		t.Fatalf("no backup of the legacy file: %v", err)
+	}
+	if string(backup) != legacy {
+		t.Errorf("backup = %q, want the legacy file %q", backup, legacy)
+	}
+	if leftovers, _ := filepath.Glob(p.Settings() + ".tmp-*"); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestInitConfigCurrentFileIsNotRewritten(t *testing.T) {
+	p := useTempPaths(t)
+	current := `{"version": 1, "config": {"auto_like_story": false}}`
+	writeSettings(t, p, current)
+
+	if err := InitConfig(); err != nil {
+		t.Fatalf("InitConfig: %v", err)
+	}
+
+	want := defaultConfig()
+	want.AutoLikeStory = false
+	if got := GetConfig(); got != want {
+		t.Errorf("config = %+v, want %+v", got, want)
+	}
+	data, _ := os.ReadFile(p.Settings())
+	if string(data) != current {
+		t.Errorf("settings.dat was rewritten: %s", data)
+	}
+	if _, err := os.Stat(p.Settings() + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("unexpected backup for a current file (err = %v)", err)
+	}
+}
+
+func TestInitConfigErrorsKeepFileAndConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantErrPart string
+	}{
+		{name: "corrupt JSON", content: `{"auto_online": tru`, wantErrPart: ".bak"},
+		{name: "future version", content: `{"version": 99, "config": {}}`, wantErrPart: "lebih baru"},
+		{name: "invalid version", content: `{"version": "x"}`, wantErrPart: "version tidak valid"},
+		{name: "wrong value type", content: `{"version": 1, "config": {"auto_online": "ya"}}`, wantErrPart: "tidak valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := useTempPaths(t)
+			writeSettings(t, p, tt.content)
+
+			before := GetConfig()
+			err := InitConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErrPart) {
+				t.Fatalf("InitConfig error = %v, want it to mention %q", err, tt.wantErrPart)
+			}
+			if got := GetConfig(); got != before {
+				t.Errorf("config changed to %+v after a failed load", got)
+			}
+			data, _ := os.ReadFile(p.Settings())
+			if string(data) != tt.content {
+				t.Errorf("settings.dat was changed to %q", data)
+			}
+			if _, err := os.Stat(p.Settings() + ".bak"); !os.IsNotExist(err) {
+				t.Errorf("unexpected backup after a failed load (err = %v)", err)
+			}
+		})
+	}
+}
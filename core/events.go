@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConfigChange is delivered to subscribers after a setting has been saved
+type ConfigChange struct {
+	Field ConfigField
+	Old   interface{}
+	New   interface{}
+	// Account is the main account whose own setting changed in
+	// multi-account mode, or "" for settings.dat
+	Account string
+}
+
+// Key is the json key of the changed setting
+func (c ConfigChange) Key() string {
+	return c.Field.Key
+}
+
+// Bool returns the new value of an on/off setting
+func (c ConfigChange) Bool() bool {
+	b, _ := c.New.(bool)
+	return b
+}
+
+// ConfigListener is called after a setting it subscribed to changes
+type ConfigListener func(change ConfigChange)
+
+type configSubscription struct {
+	id       int
+	keys     map[string]bool
+	listener ConfigListener
+}
+
+var (
+	configSubs   []configSubscription
+	configSubsID int
+	configSubsMu sync.RWMutex
+)
+
+// SubscribeConfig registers listener for changes to the given settings
+// (json key, shortcut command or key without underscores), or to every
+// setting when no key is given. Listeners run synchronously, in
+// subscription order, after the new value is saved and visible through
+// GetConfig. The returned function removes the subscription.
+func SubscribeConfig(listener ConfigListener, keys ...string) (unsubscribe func()) {
+	var keySet map[string]bool
+	if len(keys) > 0 {
+		keySet = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			field, ok := LookupConfigField(key)
+			if !ok {
+				panic(fmt.Sprintf("core: subscribe to unknown setting %q", key))
+			}
+			keySet[field.Key] = true
+		}
+	}
+
+	configSubsMu.Lock()
+	configSubsID++
+	id := configSubsID
+	configSubs = append(configSubs, configSubscription{id: id, keys: keySet, listener: listener})
+	configSubsMu.Unlock()
+
+	return func() {
+		configSubsMu.Lock()
+		defer configSubsMu.Unlock()
+		for i, sub := range configSubs {
+			if sub.id == id {
+				configSubs = append(configSubs[:i:i], configSubs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notifyConfigChange calls every listener subscribed to change's key.
+// configMutex must NOT be held, so listeners can call GetConfig.
+func notifyConfigChange(change ConfigChange) {
+	configSubsMu.RLock()
+	subs := make([]configSubscription, len(configSubs))
+	copy(subs, configSubs)
+	configSubsMu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.keys == nil || sub.keys[change.Field.Key] {
+			sub.listener(change)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"whatsapp-bot/utils"
+)
+
+// Override maps a setting key (only fields tagged override:"true") to the
+// value forced for one chat or contact. Keys that aren't present follow
+// the global setting.
+type Override map[string]bool
+
+// OverrideTable is the persisted per-target settings. Chats are keyed by
+// full JID ("xxx@g.us", "628xxx@s.whatsapp.net"); contacts, like roles, by
+// phone number digits or LID JID ("xxx@lid").
+type OverrideTable struct {
+	Chats    map[string]Override `json:"chats"`
+	Contacts map[string]Override `json:"contacts"`
+}
+
+var (
+	overrides = OverrideTable{
+		Chats:    make(map[string]Override),
+		Contacts: make(map[string]Override),
+	}
+	overrideMutex sync.RWMutex
+)
+
+func InitOverrides() error {
+	overrideMutex.Lock()
+	defer overrideMutex.Unlock()
+
+	path := GetPaths().Overrides()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", path, err)
+	}
+
+	var loaded OverrideTable
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("file %s rusak: %v", path, err)
+	}
+	if loaded.Chats == nil {
+		loaded.Chats = make(map[string]Override)
+	}
+	if loaded.Contacts == nil {
+		loaded.Contacts = make(map[string]Override)
+	}
+	for _, table := range []map[string]Override{loaded.Chats, loaded.Contacts} {
+		for target, override := range table {
+			for key := range override {
+				if field, ok := LookupConfigField(key); !ok || !field.Overridable || field.Key != key {
+					return fmt.Errorf("file %s: setting %q untuk %s tidak bisa di-override", path, key, target)
+				}
+			}
+		}
+	}
+	overrides = loaded
+	return nil
+}
+
+func saveOverrides() error {
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(GetPaths().Overrides(), data, 0o644)
+}
+
+// OverridableFields lists the settings that can be set per chat/contact
+func OverridableFields() []ConfigField {
+	var fields []ConfigField
+	for _, field := range configFields {
+		if field.Overridable {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func lookupOverridable(key string) (ConfigField, error) {
+	field, ok := LookupConfigField(key)
+	if !ok {
+		return ConfigField{}, fmt.Errorf("setting %q tidak dikenal", key)
+	}
+	if !field.Overridable {
+		return ConfigField{}, fmt.Errorf("setting %s tidak bisa diatur per chat/kontak", field.Key)
+	}
+	return field, nil
+}
+
+// setOverride stores value for a chat or contact target, or removes it when
+// value is nil. It reports whether anything changed.
+func setOverride(contact bool, target, key string, value *bool) (ConfigField, bool, error) {
+	field, err := lookupOverridable(key)
+	if err != nil {
+		return field, false, err
+	}
+
+	overrideMutex.Lock()
+	defer overrideMutex.Unlock()
+
+	table := overrides.Chats
+	if contact {
+		table = overrides.Contacts
+	}
+
+	current, exists := table[target][field.Key]
+	if (value == nil && !exists) || (value != nil && exists && current == *value) {
+		return field, false, nil
+	}
+
+	// Edit a copy so a failed save leaves memory untouched
+	updated := make(Override, len(table[target])+1)
+	for k, v := range table[target] {
+		updated[k] = v
+	}
+	if value == nil {
+		delete(updated, field.Key)
+	} else {
+		updated[field.Key] = *value
+	}
+
+	previous, hadTarget := table[target]
+	if len(updated) == 0 {
+		delete(table, target)
+	} else {
+		table[target] = updated
+	}
+	if err := saveOverrides(); err != nil {
+		if hadTarget {
+			table[target] = previous
+		} else {
+			delete(table, target)
+		}
+		return field, false, fmt.Errorf("gagal menyimpan override: %v", err)
+	}
+	return field, true, nil
+}
+
+// SetChatOverride forces key to value in chat; a nil value returns the chat
+// to the global setting
+func SetChatOverride(chat, key string, value *bool) (ConfigField, bool, error) {
+	return setOverride(false, chat, key, value)
+}
+
+// SetContactOverride forces key to value for a contact; a nil value returns
+// the contact to the global setting
+func SetContactOverride(contact, key string, value *bool) (ConfigField, bool, error) {
+	return setOverride(true, contact, key, value)
+}
+
+func copyOverrides(table map[string]Override) map[string]Override {
+	out := make(map[string]Override, len(table))
+	for target, override := range table {
+		o := make(Override, len(override))
+		for k, v := range override {
+			o[k] = v
+		}
+		out[target] = o
+	}
+	return out
+}
+
+// GetOverrides returns a copy of the whole override table
+func GetOverrides() OverrideTable {
+	overrideMutex.RLock()
+	defer overrideMutex.RUnlock()
+	return OverrideTable{
+		Chats:    copyOverrides(overrides.Chats),
+		Contacts: copyOverrides(overrides.Contacts),
+	}
+}
+
+// OverrideTargets returns the targets of table in a stable order
+func OverrideTargets(table map[string]Override) []string {
+	targets := make([]string, 0, len(table))
+	for target := range table {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// ConfigFor returns the global config with the overrides for chat and the
+// contact (any of contactIDs) applied. Contact overrides win over chat
+// overrides; either may be empty.
+func ConfigFor(chat string, contactIDs ...string) BotConfig {
+	return ApplyOverrides(GetConfig(), chat, contactIDs...)
+}
+
+// ApplyOverrides is ConfigFor on top of cfg instead of the global config,
+// e.g. a jadibot session's own settings
+func ApplyOverrides(cfg BotConfig, chat string, contactIDs ...string) BotConfig {
+	overrideMutex.RLock()
+	defer overrideMutex.RUnlock()
+
+	apply := func(override Override) {
+		for _, field := range configFields {
+			if value, ok := override[field.Key]; ok {
+				field.set(&cfg, value)
+			}
+		}
+	}
+	if chat != "" {
+		apply(overrides.Chats[chat])
+	}
+	for _, id := range contactIDs {
+		if id != "" {
+			apply(overrides.Contacts[id])
+		}
+	}
+	return cfg
+}
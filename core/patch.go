@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ConfigPatch is a partial BotConfig keyed by setting key. Profiles and
+// jadibot sessions store their settings this way, so keys they don't
+// mention keep following the config the patch is applied to.
+type ConfigPatch map[string]interface{}
+
+// PatchFrom returns a patch holding every field of cfg
+func PatchFrom(cfg BotConfig) ConfigPatch {
+	patch := make(ConfigPatch, len(configFields))
+	for _, field := range configFields {
+		patch[field.Key] = field.Get(cfg)
+	}
+	return patch
+}
+
+// SessionPatch returns a patch holding the per-session fields of cfg, the
+// starting settings of a new jadibot session
+func SessionPatch(cfg BotConfig) ConfigPatch {
+	patch := make(ConfigPatch)
+	for _, field := range configFields {
+		if field.PerSession {
+			patch[field.Key] = field.Get(cfg)
+		}
+	}
+	return patch
+}
+
+// DiffConfig returns the fields of cfg that differ from base
+func DiffConfig(base, cfg BotConfig) ConfigPatch {
+	patch := make(ConfigPatch)
+	for _, field := range configFields {
+		if value := field.Get(cfg); value != field.Get(base) {
+			patch[field.Key] = value
+		}
+	}
+	return patch
+}
+
+// Apply returns base with the patch applied. Call Normalize first on
+// patches that were decoded from JSON.
+func (p ConfigPatch) Apply(base BotConfig) BotConfig {
+	for _, field := range configFields {
+		if value, ok := p[field.Key]; ok && field.Validate(value) == nil {
+			field.set(&base, value)
+		}
+	}
+	return base
+}
+
+// Keys returns the keys of the patch in schema order
+func (p ConfigPatch) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for _, field := range configFields {
+		if _, ok := p[field.Key]; ok {
+			keys = append(keys, field.Key)
+		}
+	}
+	return keys
+}
+
+// Normalize converts values decoded from JSON (numbers arrive as float64)
+// to the field types and validates them. Unknown keys are an error.
+func (p ConfigPatch) Normalize() error {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := LookupConfigField(key)
+		if !ok || field.Key != key {
+			return fmt.Errorf("setting %q tidak dikenal", key)
+		}
+
+		value := p[key]
+		if n, ok := value.(float64); ok && field.Type.Kind() != reflect.Float64 && field.Type.Kind() != reflect.Float32 {
+			if n != float64(int64(n)) {
+				return fmt.Errorf("%s harus bertipe %s", key, field.TypeName())
+			}
+			value = reflect.ValueOf(int64(n)).Convert(field.Type).Interface()
+		}
+		if err := field.Validate(value); err != nil {
+			return err
+		}
+		p[key] = reflect.ValueOf(value).Convert(field.Type).Interface()
+	}
+	return nil
+}
+
+// ApplyConfigPatch applies patch to the main config in one save and
+// notifies subscribers of every value that changed
+func ApplyConfigPatch(patch ConfigPatch) ([]ConfigChange, error) {
+	if err := patch.Normalize(); err != nil {
+		return nil, err
+	}
+
+	configMutex.Lock()
+	updated := patch.Apply(currentConfig)
+	if updated == currentConfig {
+		configMutex.Unlock()
+		return nil, nil
+	}
+	if err := saveState(updated); err != nil {
+		configMutex.Unlock()
+		return nil, fmt.Errorf("gagal menyimpan setting: %v", err)
+	}
+	old := currentConfig
+	currentConfig = updated
+	configMutex.Unlock()
+
+	var changes []ConfigChange
+	for _, field := range configFields {
+		if change := (ConfigChange{Field: field, Old: field.Get(old), New: field.Get(updated)}); change.Old != change.New {
+			changes = append(changes, change)
+			notifyConfigChange(change)
+		}
+	}
+	return changes, nil
+}
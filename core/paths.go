@@ -0,0 +1,120 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Paths is the on-disk layout of the bot. Everything the bot writes lives
+// under DataDir; static files it only reads (the bot image) live under
+// AssetDir.
+//
+//	<data>/settings.dat            setting bot (.set/.get)
+//	<data>/roles.json              owner, admin dan role command
+//	<data>/overrides.json          setting per chat / per kontak
+//	<data>/profiles.json           profile setting (.profile)
+//	<data>/bossbot/<nomor>.db      session bot utama
+//	<data>/bossbot/<nomor>.json    setting sendiri tiap akun (multi akun)
+//	<data>/jadibot/<nomor>/        session + metadata tiap jadibot
+//	<asset>/bot.png                gambar untuk .info dan .ping
+type Paths struct {
+	DataDir  string
+	AssetDir string
+}
+
+var (
+	paths      = NewPaths(defaultRuntimeConfig().DataDir, defaultRuntimeConfig().AssetDir)
+	pathsMutex sync.RWMutex
+)
+
+func NewPaths(dataDir, assetDir string) Paths {
+	return Paths{DataDir: filepath.Clean(dataDir), AssetDir: filepath.Clean(assetDir)}
+}
+
+// GetPaths returns the active layout
+func GetPaths() Paths {
+	pathsMutex.RLock()
+	defer pathsMutex.RUnlock()
+	return paths
+}
+
+// SetPaths switches the active layout, e.g. to a temp dir. It is called by
+// LoadRuntimeConfig and must happen before any file is opened.
+func SetPaths(p Paths) {
+	pathsMutex.Lock()
+	defer pathsMutex.Unlock()
+	paths = p
+}
+
+// EnsureDirs creates the folders the bot writes into
+func (p Paths) EnsureDirs() error {
+	for _, dir := range []string{p.DataDir, p.MainSessionDir(), p.JadibotDir()} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("gagal membuat folder %s: %v", dir, err)
+		}
+	}
+	return nil
+}
+
+func (p Paths) Settings() string {
+	return filepath.Join(p.DataDir, "settings.dat")
+}
+
+func (p Paths) Roles() string {
+	return filepath.Join(p.DataDir, "roles.json")
+}
+
+func (p Paths) Overrides() string {
+	return filepath.Join(p.DataDir, "overrides.json")
+}
+
+func (p Paths) Profiles() string {
+	return filepath.Join(p.DataDir, "profiles.json")
+}
+
+func (p Paths) MainSessionDir() string {
+	return filepath.Join(p.DataDir, "bossbot")
+}
+
+func (p Paths) MainSessionDB(number string) string {
+	return filepath.Join(p.MainSessionDir(), number+".db")
+}
+
+// AccountSettings holds a main account's own settings in multi-account mode
+func (p Paths) AccountSettings(number string) string {
+	return filepath.Join(p.MainSessionDir(), number+".json")
+}
+
+func (p Paths) JadibotDir() string {
+	return filepath.Join(p.DataDir, "jadibot")
+}
+
+// JadibotSessionDir is the folder of one jadibot: its database files and
+// metadata.json
+func (p Paths) JadibotSessionDir(number string) string {
+	return filepath.Join(p.JadibotDir(), number)
+}
+
+func (p Paths) JadibotDB(number string) string {
+	return filepath.Join(p.JadibotSessionDir(number), number+".db")
+}
+
+func (p Paths) JadibotMetadata(number string) string {
+	return filepath.Join(p.JadibotSessionDir(number), "metadata.json")
+}
+
+// PairingCode and PairingQR are where headless mode writes the main bot's
+// pairing code or QR unless pairing_file is set
+func (p Paths) PairingCode() string {
+	return filepath.Join(p.DataDir, "pairing.txt")
+}
+
+func (p Paths) PairingQR() string {
+	return filepath.Join(p.DataDir, "pairing.png")
+}
+
+func (p Paths) BotImage() string {
+	return filepath.Join(p.AssetDir, "bot.png")
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"whatsapp-bot/utils"
+)
+
+const profilesVersion = 1
+
+// profilesFile is the on-disk layout of profiles.json
+type profilesFile struct {
+	Version  int                    `json:"version"`
+	Profiles map[string]ConfigPatch `json:"profiles"`
+}
+
+var (
+	profiles     = defaultProfiles()
+	profileMutex sync.RWMutex
+
+	profileNameRe = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+)
+
+// defaultProfiles are available until profiles.json is first written
+func defaultProfiles() map[string]ConfigPatch {
+	return map[string]ConfigPatch{
+		"stealth": {
+			"auto_online":        false,
+			"auto_typing":        false,
+			"auto_recording":     false,
+			"auto_read_story":    true,
+			"auto_like_story":    false,
+			"story_random_delay": true,
+		},
+		"active": {
+			"auto_online":        true,
+			"auto_typing":        true,
+			"auto_recording":     true,
+			"auto_read_story":    true,
+			"auto_like_story":    true,
+			"story_random_delay": true,
+		},
+	}
+}
+
+func InitProfiles() error {
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+
+	path := GetPaths().Profiles()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", path, err)
+	}
+
+	var loaded profilesFile
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("file %s rusak: %v", path, err)
+	}
+	if loaded.Version > profilesVersion {
+		return fmt.Errorf("file %s versi %d lebih baru dari yang didukung bot ini (%d)", path, loaded.Version, profilesVersion)
+	}
+	if loaded.Profiles == nil {
+		loaded.Profiles = make(map[string]ConfigPatch)
+	}
+	for name, patch := range loaded.Profiles {
+		if err := patch.Normalize(); err != nil {
+			return fmt.Errorf("file %s: profile %s: %v", path, name, err)
+		}
+	}
+	profiles = loaded.Profiles
+	return nil
+}
+
+func saveProfiles(all map[string]ConfigPatch) error {
+	data, err := json.MarshalIndent(profilesFile{Version: profilesVersion, Profiles: all}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(GetPaths().Profiles(), data, 0o644)
+}
+
+// NormalizeProfileName lowercases name and checks it is a valid profile name
+func NormalizeProfileName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !profileNameRe.MatchString(name) {
+		return "", fmt.Errorf("nama profile %q tidak valid (huruf kecil, angka, - atau _, maks 32)", name)
+	}
+	return name, nil
+}
+
+// GetProfile returns a copy of the named profile
+func GetProfile(name string) (ConfigPatch, bool) {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
+
+	patch, ok := profiles[name]
+	if !ok {
+		return nil, false
+	}
+	out := make(ConfigPatch, len(patch))
+	for k, v := range patch {
+		out[k] = v
+	}
+	return out, true
+}
+
+// ProfileNames lists every profile, sorted
+func ProfileNames() []string {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SaveProfile stores cfg as the named profile, replacing an existing one.
+// It reports whether a profile with that name already existed.
+func SaveProfile(name string, cfg BotConfig) (bool, error) {
+	name, err := NormalizeProfileName(name)
+	if err != nil {
+		return false, err
+	}
+
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+
+	updated := make(map[string]ConfigPatch, len(profiles)+1)
+	for k, v := range profiles {
+		updated[k] = v
+	}
+	_, existed := updated[name]
+	updated[name] = PatchFrom(cfg)
+
+	if err := saveProfiles(updated); err != nil {
+		return existed, fmt.Errorf("gagal menyimpan profile: %v", err)
+	}
+	profiles = updated
+	return existed, nil
+}
+
+// DeleteProfile removes the named profile, reporting whether it existed
+func DeleteProfile(name string) (bool, error) {
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+
+	if _, ok := profiles[name]; !ok {
+		return false, nil
+	}
+	updated := make(map[string]ConfigPatch, len(profiles))
+	for k, v := range profiles {
+		if k != name {
+			updated[k] = v
+		}
+	}
+	if err := saveProfiles(updated); err != nil {
+		return true, fmt.Errorf("gagal menyimpan profile: %v", err)
+	}
+	profiles = updated
+	return true, nil
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"whatsapp-bot/utils"
+)
+
+// RoleConfig is the persisted permission table. Owners and admins are
+// stored as phone numbers (digits only) or full LID JIDs ("xxx@lid").
+type RoleConfig struct {
+	Owners       []string          `json:"owners"`
+	Admins       []string          `json:"admins"`
+	CommandRoles map[string]string `json:"command_roles,omitempty"`
+}
+
+var (
+	roleConfig = RoleConfig{
+		CommandRoles: make(map[string]string),
+	}
+	roleMutex sync.RWMutex
+)
+
+func InitRoles() error {
+	roleMutex.Lock()
+	defer roleMutex.Unlock()
+
+	path := GetPaths().Roles()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gagal membaca %s: %v", path, err)
+	}
+
+	var loaded RoleConfig
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("file %s rusak: %v", path, err)
+	}
+	if loaded.CommandRoles == nil {
+		loaded.CommandRoles = make(map[string]string)
+	}
+	roleConfig = loaded
+	return nil
+}
+
+func saveRoles(cfg RoleConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(GetPaths().Roles(), data, 0o644)
+}
+
+func (c RoleConfig) clone() RoleConfig {
+	cfg := RoleConfig{
+		Owners:       append([]string(nil), c.Owners...),
+		Admins:       append([]string(nil), c.Admins...),
+		CommandRoles: make(map[string]string, len(c.CommandRoles)),
+	}
+	for cmd, role := range c.CommandRoles {
+		cfg.CommandRoles[cmd] = role
+	}
+	return cfg
+}
+
+// updateRoles applies edit to a copy of the role table and swaps it in only
+// once it is saved, so a failed save leaves memory matching roles.json.
+// edit reports whether it changed anything; nothing is saved otherwise.
+func updateRoles(edit func(cfg *RoleConfig) bool) (bool, error) {
+	roleMutex.Lock()
+	defer roleMutex.Unlock()
+
+	updated := roleConfig.clone()
+	if !edit(&updated) {
+		return false, nil
+	}
+	if err := saveRoles(updated); err != nil {
+		return false, err
+	}
+	roleConfig = updated
+	return true, nil
+}
+
+func GetRoleConfig() RoleConfig {
+	roleMutex.RLock()
+	defer roleMutex.RUnlock()
+	return roleConfig.clone()
+}
+
+func containsAny(list []string, ids []string) bool {
+	for _, entry := range list {
+		for _, id := range ids {
+			if id != "" && entry == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsOwner reports whether any of ids is a configured owner
+func IsOwner(ids ...string) bool {
+	roleMutex.RLock()
+	defer roleMutex.RUnlock()
+	return containsAny(roleConfig.Owners, ids)
+}
+
+// IsAdmin reports whether any of ids is a configured admin
+func IsAdmin(ids ...string) bool {
+	roleMutex.RLock()
+	defer roleMutex.RUnlock()
+	return containsAny(roleConfig.Admins, ids)
+}
+
+func addEntry(list func(cfg *RoleConfig) *[]string, id string) (bool, error) {
+	return updateRoles(func(cfg *RoleConfig) bool {
+		entries := list(cfg)
+		for _, entry := range *entries {
+			if entry == id {
+				return false
+			}
+		}
+		*entries = append(*entries, id)
+		sort.Strings(*entries)
+		return true
+	})
+}
+
+func removeEntry(list func(cfg *RoleConfig) *[]string, id string) (bool, error) {
+	return updateRoles(func(cfg *RoleConfig) bool {
+		entries := list(cfg)
+		for i, entry := range *entries {
+			if entry == id {
+				*entries = append((*entries)[:i], (*entries)[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func owners(cfg *RoleConfig) *[]string { return &cfg.Owners }
+func admins(cfg *RoleConfig) *[]string { return &cfg.Admins }
+
+func AddOwner(id string) (bool, error) {
+	return addEntry(owners, id)
+}
+
+func RemoveOwner(id string) (bool, error) {
+	return removeEntry(owners, id)
+}
+
+func AddAdmin(id string) (bool, error) {
+	return addEntry(admins, id)
+}
+
+func RemoveAdmin(id string) (bool, error) {
+	return removeEntry(admins, id)
+}
+
+// GetCommandRole returns the stored minimum role override for a command
+func GetCommandRole(command string) (string, bool) {
+	roleMutex.RLock()
+	defer roleMutex.RUnlock()
+	role, ok := roleConfig.CommandRoles[strings.ToLower(command)]
+	return role, ok
+}
+
+// SetCommandRole stores a minimum role override for a command. An empty
+// role removes the override.
+func SetCommandRole(command, role string) error {
+	command = strings.ToLower(command)
+	_, err := updateRoles(func(cfg *RoleConfig) bool {
+		if role == "" {
+			delete(cfg.CommandRoles, command)
+		} else {
+			cfg.CommandRoles[command] = role
+		}
+		return true
+	})
+	return err
+}
@@ -0,0 +1,508 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"whatsapp-bot/utils"
+)
+
+// RuntimeConfig holds the startup settings that can't be changed from
+// chat. Every leaf field is resolved, from lowest to highest precedence,
+// from its default, the YAML config file, an environment variable and a
+// command line flag:
+//
+//	yaml - key in the config file; nested structs become sections
+//	env  - environment variables, first non-empty wins
+//	       (default BOT_<SECTION>_<KEY>)
+//	flag - command line flag (default <section>-<key>)
+//	desc - help text for --help and --print-config
+type RuntimeConfig struct {
+	DataDir       string   `yaml:"data_dir" desc:"Folder data bot (session, setting, role, jadibot)"`
+	AssetDir      string   `yaml:"asset_dir" desc:"Folder file statis (gambar bot)"`
+	Timezone      string   `yaml:"timezone" desc:"Zona waktu untuk jam di log story"`
+	DefaultRegion string   `yaml:"default_region" flag:"region" desc:"Kode negara (ISO, mis. ID, MY) untuk nomor format lokal seperti 0812xxx"`
+	PhoneNumber   string   `yaml:"phone_number" env:"WHATSAPP_NUMBER,NOMOR_BOT" flag:"number" desc:"Nomor bot utama; kosong = pilih/tanya saat start"`
+	PairingMethod int      `yaml:"pairing_method" env:"WHATSAPP_PAIRING_METHOD" flag:"pairing" desc:"1 = kode pairing, 2 = QR code, 0 = tanya saat start"`
+	Headless      bool     `yaml:"headless" desc:"Tidak pernah membaca stdin (systemd/container); nomor & metode pairing wajib dari konfigurasi"`
+	PairingFile   string   `yaml:"pairing_file" desc:"Mode headless: file kode pairing (teks) / QR (PNG); kosong = <data_dir>/pairing.txt atau pairing.png"`
+	PairingAddr   string   `yaml:"pairing_addr" desc:"Mode headless: alamat HTTP lokal yang menampilkan kode pairing/QR (mis. 127.0.0.1:8080); kosong = mati"`
+	MultiAccount  bool     `yaml:"multi_account" desc:"Jalankan semua session bot utama yang valid sekaligus, masing-masing dengan setting dan jadibot sendiri"`
+	Accounts      []string `yaml:"accounts" desc:"Daftar nomor bot utama yang dijalankan sekaligus (pisahkan dengan koma); mengaktifkan multi_account"`
+
+	Story       StoryRuntime       `yaml:"story"`
+	Presence    PresenceRuntime    `yaml:"presence"`
+	HealthCheck HealthCheckRuntime `yaml:"health_check"`
+	Reconnect   ReconnectRuntime   `yaml:"reconnect"`
+	Jadibot     JadibotRuntime     `yaml:"jadibot"`
+
+	location *time.Location
+}
+
+type StoryRuntime struct {
+	MinDelay    time.Duration `yaml:"min_delay" desc:"Delay minimum sebelum lihat story (story_random_delay on)"`
+	MaxDelay    time.Duration `yaml:"max_delay" desc:"Delay maksimum sebelum lihat story (story_random_delay on)"`
+	NormalDelay time.Duration `yaml:"normal_delay" desc:"Delay sebelum lihat story (story_random_delay off)"`
+	Cooldown    time.Duration `yaml:"cooldown" desc:"Abaikan story yang sama dalam rentang ini"`
+}
+
+type PresenceRuntime struct {
+	MinDelay time.Duration `yaml:"min_delay" desc:"Delay minimum sebelum mulai typing/recording"`
+	MaxDelay time.Duration `yaml:"max_delay" desc:"Delay maksimum sebelum mulai typing/recording"`
+	Duration time.Duration `yaml:"duration" desc:"Lama status typing/recording ditampilkan"`
+}
+
+type HealthCheckRuntime struct {
+	Interval           time.Duration `yaml:"interval" desc:"Interval cek koneksi semua session (bot utama & jadibot)"`
+	CheckpointInterval time.Duration `yaml:"checkpoint_interval" desc:"Interval WAL checkpoint database semua session"`
+	MaxFails           int           `yaml:"max_fails" desc:"Gagal berturut-turut sebelum jadibot dihapus (bot utama terus dicoba)"`
+	FailReset          time.Duration `yaml:"fail_reset" desc:"Reset hitungan gagal session setelah rentang ini tanpa gagal"`
+}
+
+type ReconnectRuntime struct {
+	BaseDelay       time.Duration `yaml:"base_delay" desc:"Delay reconnect awal, berlipat dua tiap percobaan (dengan jitter), semua session"`
+	MaxDelay        time.Duration `yaml:"max_delay" desc:"Batas delay reconnect"`
+	MaxAttempts     int           `yaml:"max_attempts" desc:"Jumlah percobaan per rangkaian reconnect sebelum circuit dibuka"`
+	CircuitCooldown time.Duration `yaml:"circuit_cooldown" desc:"Jeda tanpa reconnect setelah satu rangkaian gagal semua (circuit terbuka)"`
+}
+
+type JadibotRuntime struct {
+	PairingTimeout  time.Duration `yaml:"pairing_timeout" desc:"Batas waktu menunggu pairing jadibot"`
+	MaxSessions     int           `yaml:"max_sessions" desc:"Maksimal jadibot aktif + pairing sekaligus; 0 = tanpa batas"`
+	MaxPerOwner     int           `yaml:"max_per_owner" desc:"Maksimal jadibot per pendaftar (owner bot tidak dibatasi); 0 = tanpa batas"`
+	DefaultDuration time.Duration `yaml:"default_duration" desc:"Masa aktif jadibot baru; 0 = tanpa batas"`
+	ExpiryWarning   time.Duration `yaml:"expiry_warning" desc:"Kirim peringatan sebelum masa aktif jadibot habis"`
+	NotifyInterval  time.Duration `yaml:"notify_interval" desc:"Jeda minimal notif jenis yang sama per jadibot (putus/terhubung lagi); 0 = selalu kirim"`
+	NotifySelf      bool          `yaml:"notify_self" desc:"Kirim notif jadibot juga ke nomor jadibot itu sendiri"`
+}
+
+// defaultConfigFile is read when no --config/BOT_CONFIG is given; it's
+// optional, unlike an explicitly named file
+const defaultConfigFile = "config.yaml"
+
+func defaultRuntimeConfig() RuntimeConfig {
+	return RuntimeConfig{
+		DataDir:       "Wilykun",
+		AssetDir:      "img",
+		Timezone:      "Asia/Jakarta",
+		DefaultRegion: "ID",
+		Story: StoryRuntime{
+			MinDelay:    1 * time.Second,
+			MaxDelay:    20 * time.Second,
+			NormalDelay: 1 * time.Second,
+			Cooldown:    3 * time.Second,
+		},
+		Presence: PresenceRuntime{
+			MinDelay: 500 * time.Millisecond,
+			MaxDelay: 1500 * time.Millisecond,
+			Duration: 15 * time.Second,
+		},
+		HealthCheck: HealthCheckRuntime{
+			Interval:           30 * time.Second,
+			CheckpointInterval: 5 * time.Minute,
+			MaxFails:           10,
+			FailReset:          5 * time.Minute,
+		},
+		Reconnect: ReconnectRuntime{
+			BaseDelay:       5 * time.Second,
+			MaxDelay:        60 * time.Second,
+			MaxAttempts:     5,
+			CircuitCooldown: 10 * time.Minute,
+		},
+		Jadibot: JadibotRuntime{
+			PairingTimeout: 180 * time.Second,
+			ExpiryWarning:  24 * time.Hour,
+			NotifyInterval: 15 * time.Minute,
+		},
+	}
+}
+
+var (
+	runtimeConfig  = defaultRuntimeConfig()
+	runtimeSources = make(map[string]string)
+	runtimeFile    string
+)
+
+func init() {
+	runtimeConfig.location, _ = time.LoadLocation(runtimeConfig.Timezone)
+}
+
+// Runtime returns the startup configuration. It is loaded once by
+// LoadRuntimeConfig before anything else runs and must not be modified.
+func Runtime() *RuntimeConfig {
+	return &runtimeConfig
+}
+
+// Location is the configured timezone
+func (c *RuntimeConfig) Location() *time.Location {
+	if c.location == nil {
+		return time.Local
+	}
+	return c.location
+}
+
+// Now is the current time in the configured timezone
+func (c *RuntimeConfig) Now() time.Time {
+	return time.Now().In(c.Location())
+}
+
+// Delay returns the wait before reconnect attempt n (1-based): a random
+// duration up to base_delay doubled per attempt, capped at max_delay (full
+// jitter), so sessions that dropped together don't retry together
+func (r ReconnectRuntime) Delay(attempt int) time.Duration {
+	ceiling := r.MaxDelay
+	if attempt < 1 {
+		attempt = 1
+	}
+	if shift := attempt - 1; shift < 32 {
+		if d := r.BaseDelay << shift; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// runtimeOption is one leaf field of RuntimeConfig
+type runtimeOption struct {
+	path  string
+	envs  []string
+	flag  string
+	desc  string
+	value reflect.Value
+}
+
+func runtimeOptions(cfg *RuntimeConfig) []runtimeOption {
+	var options []runtimeOption
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+			if !sf.IsExported() || key == "" || key == "-" {
+				continue
+			}
+			path := prefix + key
+			if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Duration(0)) {
+				walk(v.Field(i), path+".")
+				continue
+			}
+
+			option := runtimeOption{
+				path:  path,
+				flag:  sf.Tag.Get("flag"),
+				desc:  sf.Tag.Get("desc"),
+				value: v.Field(i),
+			}
+			if env := sf.Tag.Get("env"); env != "" {
+				option.envs = strings.Split(env, ",")
+			} else {
+				option.envs = []string{"BOT_" + strings.ToUpper(strings.ReplaceAll(path, ".", "_"))}
+			}
+			if option.flag == "" {
+				option.flag = strings.NewReplacer(".", "-", "_", "-").Replace(path)
+			}
+			options = append(options, option)
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return options
+}
+
+// setRuntimeValue parses s into an option of type string, int, bool,
+// time.Duration or []string (comma separated)
+func setRuntimeValue(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(s)
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("%q bukan angka", s)
+		}
+		v.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%q bukan true/false", s)
+		}
+		v.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%q bukan durasi (contoh: 500ms, 30s, 5m)", s)
+		}
+		v.SetInt(int64(d))
+	case []string:
+		var list []string
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, part)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("tipe %s tidak didukung", v.Type())
+	}
+	return nil
+}
+
+func formatRuntimeValue(v reflect.Value) string {
+	if list, ok := v.Interface().([]string); ok {
+		if len(list) == 0 {
+			return `""`
+		}
+		return strings.Join(list, ",")
+	}
+	if s, ok := v.Interface().(string); ok && s == "" {
+		return `""`
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// yamlKeys collects the dotted paths of every key present in a YAML
+// document, so --print-config can tell which values came from the file
+func yamlKeys(doc map[string]interface{}, prefix string, keys map[string]bool) {
+	for key, value := range doc {
+		keys[prefix+key] = true
+		if nested, ok := value.(map[string]interface{}); ok {
+			yamlKeys(nested, prefix+key+".", keys)
+		}
+	}
+}
+
+// LoadRuntimeConfig resolves the startup configuration from defaults, the
+// config file, the environment and args (os.Args[1:]). It reports whether
+// --print-config was given. --help returns flag.ErrHelp.
+func LoadRuntimeConfig(args []string) (printConfig bool, err error) {
+	cfg := defaultRuntimeConfig()
+	options := runtimeOptions(&cfg)
+	sources := make(map[string]string, len(options))
+	for _, option := range options {
+		sources[option.path] = "default"
+	}
+
+	fs := flag.NewFlagSet("whatsapp-bot", flag.ContinueOnError)
+	configPath := fs.String("config", "", "File konfigurasi YAML (env BOT_CONFIG, default "+defaultConfigFile+" jika ada)")
+	fs.BoolVar(&printConfig, "print-config", false, "Tampilkan konfigurasi efektif beserta sumbernya lalu keluar")
+
+	flagValues := make(map[string]string)
+	for _, option := range options {
+		option := option
+		usage := fmt.Sprintf("%s (env %s, default %s)", option.desc, strings.Join(option.envs, "/"), formatRuntimeValue(option.value))
+		set := func(s string) error {
+			if err := setRuntimeValue(reflect.New(option.value.Type()).Elem(), s); err != nil {
+				return err
+			}
+			flagValues[option.path] = s
+			return nil
+		}
+		// Bool flags may be given without a value (--headless)
+		if option.value.Kind() == reflect.Bool {
+			fs.BoolFunc(option.flag, usage, set)
+		} else {
+			fs.Func(option.flag, usage, set)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return false, err
+	}
+	if fs.NArg() > 0 {
+		return false, fmt.Errorf("argumen tidak dikenal: %s", strings.Join(fs.Args(), " "))
+	}
+
+	// Config file
+	file, explicit := *configPath, true
+	if file == "" {
+		file = os.Getenv("BOT_CONFIG")
+	}
+	if file == "" {
+		file, explicit = defaultConfigFile, false
+	}
+	data, err := os.ReadFile(file)
+	switch {
+	case err == nil:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("file konfigurasi %s tidak valid: %v", file, err)
+		}
+
+		var doc map[string]interface{}
+		yaml.Unmarshal(data, &doc)
+		present := make(map[string]bool)
+		yamlKeys(doc, "", present)
+		for _, option := range options {
+			if present[option.path] {
+				sources[option.path] = "file " + file
+			}
+		}
+		runtimeFile = file
+	case os.IsNotExist(err) && !explicit:
+		runtimeFile = ""
+	default:
+		return false, fmt.Errorf("gagal membaca file konfigurasi %s: %v", file, err)
+	}
+
+	// Environment
+	for _, option := range options {
+		for _, env := range option.envs {
+			value := os.Getenv(env)
+			if value == "" {
+				continue
+			}
+			// Before startup settings were validated, any other value of
+			// WHATSAPP_PAIRING_METHOD meant QR code; deployments relying on
+			// that keep starting, with a warning
+			if env == "WHATSAPP_PAIRING_METHOD" {
+				if v := strings.TrimSpace(value); v != "1" && v != "2" {
+					fmt.Printf("⚠️ env %s=%q tidak valid (harus 1 atau 2), memakai 2 (QR code)\n", env, value)
+					value = "2"
+				}
+			}
+			if err := setRuntimeValue(option.value, value); err != nil {
+				return false, fmt.Errorf("env %s: %v", env, err)
+			}
+			sources[option.path] = "env " + env
+			break
+		}
+	}
+
+	// Flags
+	for _, option := range options {
+		if value, ok := flagValues[option.path]; ok {
+			setRuntimeValue(option.value, value)
+			sources[option.path] = "flag --" + option.flag
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return false, err
+	}
+
+	runtimeConfig = cfg
+	runtimeSources = sources
+	SetPaths(NewPaths(cfg.DataDir, cfg.AssetDir))
+	return printConfig, nil
+}
+
+func (c *RuntimeConfig) validate() error {
+	if strings.TrimSpace(c.DataDir) == "" {
+		return fmt.Errorf("data_dir tidak boleh kosong")
+	}
+	if strings.TrimSpace(c.AssetDir) == "" {
+		return fmt.Errorf("asset_dir tidak boleh kosong")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone %q tidak dikenal: %v", c.Timezone, err)
+	}
+	c.location = loc
+
+	c.DefaultRegion = strings.ToUpper(strings.TrimSpace(c.DefaultRegion))
+	if !utils.IsSupportedRegion(c.DefaultRegion) {
+		return fmt.Errorf("default_region %q bukan kode negara yang dikenal (contoh: ID, MY, US)", c.DefaultRegion)
+	}
+
+	if c.PairingMethod < 0 || c.PairingMethod > 2 {
+		return fmt.Errorf("pairing_method harus 0, 1 atau 2")
+	}
+	seen := make(map[string]bool, len(c.Accounts))
+	accounts := make([]string, 0, len(c.Accounts))
+	for _, account := range c.Accounts {
+		phone, err := utils.ParsePhoneNumber(account, c.DefaultRegion)
+		if err != nil {
+			return fmt.Errorf("accounts: %v", err)
+		}
+		if !seen[phone.Number] {
+			seen[phone.Number] = true
+			accounts = append(accounts, phone.Number)
+		}
+	}
+	c.Accounts = accounts
+	if len(c.Accounts) > 0 {
+		c.MultiAccount = true
+	}
+	if c.Story.MinDelay > c.Story.MaxDelay {
+		return fmt.Errorf("story.min_delay (%v) lebih besar dari story.max_delay (%v)", c.Story.MinDelay, c.Story.MaxDelay)
+	}
+	if c.Presence.MinDelay > c.Presence.MaxDelay {
+		return fmt.Errorf("presence.min_delay (%v) lebih besar dari presence.max_delay (%v)", c.Presence.MinDelay, c.Presence.MaxDelay)
+	}
+
+	positive := map[string]time.Duration{
+		"health_check.interval":            c.HealthCheck.Interval,
+		"health_check.checkpoint_interval": c.HealthCheck.CheckpointInterval,
+		"jadibot.pairing_timeout":          c.Jadibot.PairingTimeout,
+		"reconnect.base_delay":             c.Reconnect.BaseDelay,
+		"reconnect.circuit_cooldown":       c.Reconnect.CircuitCooldown,
+	}
+	for name, d := range positive {
+		if d <= 0 {
+			return fmt.Errorf("%s harus lebih dari 0", name)
+		}
+	}
+	if c.HealthCheck.MaxFails < 1 {
+		return fmt.Errorf("health_check.max_fails minimal 1")
+	}
+	if c.Jadibot.MaxSessions < 0 || c.Jadibot.MaxPerOwner < 0 {
+		return fmt.Errorf("jadibot.max_sessions dan jadibot.max_per_owner tidak boleh negatif")
+	}
+	if c.Jadibot.DefaultDuration < 0 || c.Jadibot.ExpiryWarning < 0 || c.Jadibot.NotifyInterval < 0 {
+		return fmt.Errorf("jadibot.default_duration, jadibot.expiry_warning dan jadibot.notify_interval tidak boleh negatif")
+	}
+	if c.Reconnect.MaxAttempts < 1 {
+		return fmt.Errorf("reconnect.max_attempts minimal 1")
+	}
+	if c.Reconnect.MaxDelay < c.Reconnect.BaseDelay {
+		return fmt.Errorf("reconnect.base_delay (%v) lebih besar dari reconnect.max_delay (%v)", c.Reconnect.BaseDelay, c.Reconnect.MaxDelay)
+	}
+	return nil
+}
+
+// FormatRuntimeConfig lists every effective startup setting with the
+// layer it came from, for --print-config
+func FormatRuntimeConfig() string {
+	cfg := runtimeConfig
+	options := runtimeOptions(&cfg)
+	sort.SliceStable(options, func(i, j int) bool {
+		return strings.Count(options[i].path, ".") < strings.Count(options[j].path, ".")
+	})
+
+	width := 0
+	for _, option := range options {
+		if len(option.path) > width {
+			width = len(option.path)
+		}
+	}
+
+	var out strings.Builder
+	file := runtimeFile
+	if file == "" {
+		file = "(tidak ada)"
+	}
+	out.WriteString("⚙️ Konfigurasi efektif\n")
+	out.WriteString("File: " + file + "\n")
+	out.WriteString("Prioritas: default < file < env < flag\n\n")
+	for _, option := range options {
+		fmt.Fprintf(&out, "%-*s = %-24s (%s)\n", width, option.path, formatRuntimeValue(option.value), runtimeSources[option.path])
+	}
+	return out.String()
+}
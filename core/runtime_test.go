@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	r := ReconnectRuntime{BaseDelay: 5 * time.Second, MaxDelay: 60 * time.Second}
+
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{attempt: 0, ceiling: 5 * time.Second},
+		{attempt: 1, ceiling: 5 * time.Second},
+		{attempt: 2, ceiling: 10 * time.Second},
+		{attempt: 3, ceiling: 20 * time.Second},
+		{attempt: 4, ceiling: 40 * time.Second},
+		{attempt: 5, ceiling: 60 * time.Second},
+		{attempt: 10, ceiling: 60 * time.Second},
+		// Shifts that would overflow stay at max_delay
+		{attempt: 40, ceiling: 60 * time.Second},
+		{attempt: 100, ceiling: 60 * time.Second},
+	}
+
+	for _, tt := range tests {
+		var longest time.Duration
+		for i := 0; i < 500; i++ {
+			d := r.Delay(tt.attempt)
+			if d < 0 || d > tt.ceiling {
+				t.Fatalf("Delay(%d) = %v, want within [0, %v]", tt.attempt, d, tt.ceiling)
+			}
+			if d > longest {
+				longest = d
+			}
+		}
+		// Full jitter spreads over the whole range, not just the bottom
+		if longest < tt.ceiling/2 {
+			t.Errorf("Delay(%d) never above %v in 500 tries (ceiling %v)", tt.attempt, longest, tt.ceiling)
+		}
+	}
+
+	if d := (ReconnectRuntime{}).Delay(3); d != 0 {
+		t.Errorf("Delay with no delays configured = %v, want 0", d)
+	}
+	if d := (ReconnectRuntime{BaseDelay: time.Minute, MaxDelay: time.Second}).Delay(1); d > time.Second {
+		t.Errorf("Delay = %v, want capped at max_delay %v", d, time.Second)
+	}
+}
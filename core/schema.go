@@ -0,0 +1,235 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigField describes one BotConfig field, read from its struct tags:
+//
+//	json   - key used in settings.dat and by .set/.get
+//	label  - human readable name
+//	desc   - short description shown by .get
+//	cmd    - optional shortcut command (e.g. "online" for .online on/off)
+//	icon   - emoji shown in .status
+//	on/off - optional wording for a bool's state (e.g. "Random (1-20s)")
+//	range  - allowed range for numbers, "min-max"
+//	override - "true" if .chatset/.contactset may override it per target
+//	session  - "true" if a jadibot session keeps its own copy (.jadibotset)
+type ConfigField struct {
+	Key         string
+	Label       string
+	Description string
+	Command     string
+	Icon        string
+	OnText      string
+	OffText     string
+	Type        reflect.Type
+	Min         *int64
+	Max         *int64
+	Overridable bool
+	PerSession  bool
+	index       int
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+var configFields = buildConfigFields()
+
+func buildConfigFields() []ConfigField {
+	t := reflect.TypeOf(BotConfig{})
+	fields := make([]ConfigField, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+
+		field := ConfigField{
+			Key:         key,
+			Label:       sf.Tag.Get("label"),
+			Description: sf.Tag.Get("desc"),
+			Command:     sf.Tag.Get("cmd"),
+			Icon:        sf.Tag.Get("icon"),
+			OnText:      sf.Tag.Get("on"),
+			OffText:     sf.Tag.Get("off"),
+			Type:        sf.Type,
+			Overridable: sf.Tag.Get("override") == "true",
+			PerSession:  sf.Tag.Get("session") == "true",
+			index:       i,
+		}
+		if field.Label == "" {
+			field.Label = sf.Name
+		}
+		if field.OnText == "" {
+			field.OnText = "ON"
+		}
+		if field.OffText == "" {
+			field.OffText = "OFF"
+		}
+
+		if r := sf.Tag.Get("range"); r != "" {
+			minStr, maxStr, ok := strings.Cut(r, "-")
+			min, errMin := strconv.ParseInt(minStr, 10, 64)
+			max, errMax := strconv.ParseInt(maxStr, 10, 64)
+			if !ok || errMin != nil || errMax != nil {
+				panic(fmt.Sprintf("core: invalid range tag %q on %s", r, sf.Name))
+			}
+			field.Min, field.Max = &min, &max
+		}
+
+		if field.Overridable && !field.IsBool() {
+			panic(fmt.Sprintf("core: override tag on non-bool field %s", sf.Name))
+		}
+
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// ConfigFields returns the metadata of every configurable field
+func ConfigFields() []ConfigField {
+	fields := make([]ConfigField, len(configFields))
+	copy(fields, configFields)
+	return fields
+}
+
+// SessionFields lists the settings each jadibot session keeps its own copy of
+func SessionFields() []ConfigField {
+	var fields []ConfigField
+	for _, field := range configFields {
+		if field.PerSession {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// LookupConfigField finds a field by json key, shortcut command, or key
+// without underscores ("autoonline")
+func LookupConfigField(name string) (ConfigField, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, field := range configFields {
+		if name == field.Key || (field.Command != "" && name == field.Command) ||
+			name == strings.ReplaceAll(field.Key, "_", "") {
+			return field, true
+		}
+	}
+	return ConfigField{}, false
+}
+
+// IsBool reports whether the field is an on/off toggle
+func (f ConfigField) IsBool() bool {
+	return f.Type.Kind() == reflect.Bool
+}
+
+// IsDuration reports whether the field holds a time.Duration
+func (f ConfigField) IsDuration() bool {
+	return f.Type == durationType
+}
+
+// TypeName is the field type as shown to users
+func (f ConfigField) TypeName() string {
+	switch {
+	case f.IsBool():
+		return "on/off"
+	case f.IsDuration():
+		return "durasi"
+	case f.Type.Kind() == reflect.String:
+		return "teks"
+	}
+	return "angka"
+}
+
+// RangeText describes the allowed range, or "" if unrestricted
+func (f ConfigField) RangeText() string {
+	if f.Min == nil || f.Max == nil {
+		return ""
+	}
+	if f.IsDuration() {
+		return fmt.Sprintf("%v - %v", time.Duration(*f.Min), time.Duration(*f.Max))
+	}
+	return fmt.Sprintf("%d - %d", *f.Min, *f.Max)
+}
+
+// Get reads the field from cfg
+func (f ConfigField) Get(cfg BotConfig) interface{} {
+	return reflect.ValueOf(cfg).Field(f.index).Interface()
+}
+
+// Format renders value the way it is shown in chat
+func (f ConfigField) Format(value interface{}) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return f.OnText
+		}
+		return f.OffText
+	}
+	return fmt.Sprint(value)
+}
+
+// Validate checks that value has the right type and is inside the range
+func (f ConfigField) Validate(value interface{}) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().ConvertibleTo(f.Type) || v.Kind() != f.Type.Kind() {
+		return fmt.Errorf("%s harus bertipe %s", f.Key, f.TypeName())
+	}
+
+	if f.Min != nil && f.Max != nil {
+		n := v.Convert(f.Type).Int()
+		if n < *f.Min || n > *f.Max {
+			return fmt.Errorf("%s harus di antara %s", f.Key, f.RangeText())
+		}
+	}
+	return nil
+}
+
+func (f ConfigField) set(cfg *BotConfig, value interface{}) {
+	reflect.ValueOf(cfg).Elem().Field(f.index).Set(reflect.ValueOf(value).Convert(f.Type))
+}
+
+// GetConfigValue returns the current value of the field named key
+func GetConfigValue(key string) (ConfigField, interface{}, error) {
+	field, ok := LookupConfigField(key)
+	if !ok {
+		return ConfigField{}, nil, fmt.Errorf("setting %q tidak dikenal", key)
+	}
+	return field, field.Get(GetConfig()), nil
+}
+
+// SetConfigValue validates and stores a new value for key, returning the
+// field and its previous value. Subscribers are notified if the value
+// actually changed.
+func SetConfigValue(key string, value interface{}) (ConfigField, interface{}, error) {
+	field, ok := LookupConfigField(key)
+	if !ok {
+		return ConfigField{}, nil, fmt.Errorf("setting %q tidak dikenal", key)
+	}
+	if err := field.Validate(value); err != nil {
+		return field, nil, err
+	}
+
+	configMutex.Lock()
+
+	// Save first so memory never disagrees with settings.dat
+	updated := currentConfig
+	field.set(&updated, value)
+	if err := saveState(updated); err != nil {
+		configMutex.Unlock()
+		return field, nil, fmt.Errorf("gagal menyimpan setting: %v", err)
+	}
+
+	old := field.Get(currentConfig)
+	currentConfig = updated
+	configMutex.Unlock()
+
+	if change := (ConfigChange{Field: field, Old: old, New: field.Get(updated)}); change.Old != change.New {
+		notifyConfigChange(change)
+	}
+	return field, old, nil
+}
@@ -2,34 +2,53 @@ package features
 
 import (
 	"context"
-	"math/rand"
-	"sync/atomic"
+	"fmt"
 	"time"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
-)
 
-var (
-	autoTypingEnabled    atomic.Bool
-	autoRecordingEnabled atomic.Bool
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
 )
 
-func GetAutoTypingEnabled() bool {
-	return autoTypingEnabled.Load()
-}
-
-func SetAutoTypingEnabled(val bool) {
-	autoTypingEnabled.Store(val)
+func init() {
+	core.SubscribeConfig(func(change core.ConfigChange) {
+		jm := GetJadibotManager()
+		for _, session := range jm.GetAllSessions() {
+			// A main account's own setting only reaches its jadibots
+			if change.Account != "" && session.Account != change.Account {
+				continue
+			}
+			// Sessions with their own auto_online keep it
+			cfg, _ := jm.SessionConfig(session.PhoneNumber)
+			if cfg.AutoOnline == change.Bool() && session.Client != nil && session.Client.IsConnected() {
+				go SendOnlinePresence(session.Client, cfg.AutoOnline)
+			}
+		}
+	}, "auto_online")
 }
 
-func GetAutoRecordingEnabled() bool {
-	return autoRecordingEnabled.Load()
+// clientAccount is the phone number of the account client is logged in as,
+// "" before pairing
+func clientAccount(client *whatsmeow.Client) string {
+	if client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.User
 }
 
-func SetAutoRecordingEnabled(val bool) {
-	autoRecordingEnabled.Store(val)
+// SendOnlinePresence shows client as online or offline, used when
+// auto_online is toggled
+func SendOnlinePresence(client *whatsmeow.Client, online bool) {
+	presence := types.PresenceUnavailable
+	if online {
+		presence = types.PresenceAvailable
+	}
+	if err := client.SendPresence(context.Background(), presence); err != nil {
+		fmt.Printf("%s⚠️ %sGagal mengubah presence: %v%s\n", ColorYellow, core.AccountTag(clientAccount(client)), err, ColorReset)
+	}
 }
 
 func HandleAutoPresence(client *whatsmeow.Client, msg *events.Message) {
@@ -46,41 +65,44 @@ func HandleAutoPresence(client *whatsmeow.Client, msg *events.Message) {
 
 	ctx := context.Background()
 
-	if GetAutoTypingEnabled() {
+	// Per-chat and per-contact overrides (.chatset/.contactset) win over
+	// the account's setting
+	ids, _ := utils.ContactIDs(client, msg.Info.Sender)
+	cfg := core.ApplyOverrides(core.AccountConfig(botJID), msg.Info.Chat.ToNonAD().String(), ids...)
+
+	if cfg.AutoTyping {
 		go sendAutoTyping(ctx, client, msg.Info.Chat)
 	}
 
-	if GetAutoRecordingEnabled() {
+	if cfg.AutoRecording {
 		go sendAutoRecording(ctx, client, msg.Info.Chat)
 	}
 }
 
 func sendAutoTyping(ctx context.Context, client *whatsmeow.Client, chat types.JID) {
-	delay := time.Duration(500+rand.Intn(1000)) * time.Millisecond
-	time.Sleep(delay)
+	presence := core.Runtime().Presence
+	time.Sleep(randomDuration(presence.MinDelay, presence.MaxDelay))
 
 	err := client.SendChatPresence(ctx, chat, types.ChatPresenceComposing, types.ChatPresenceMediaText)
 	if err != nil {
 		return
 	}
 
-	typingDuration := 15 * time.Second
-	time.Sleep(typingDuration)
+	time.Sleep(presence.Duration)
 
 	client.SendChatPresence(ctx, chat, types.ChatPresencePaused, types.ChatPresenceMediaText)
 }
 
 func sendAutoRecording(ctx context.Context, client *whatsmeow.Client, chat types.JID) {
-	delay := time.Duration(500+rand.Intn(1000)) * time.Millisecond
-	time.Sleep(delay)
+	presence := core.Runtime().Presence
+	time.Sleep(randomDuration(presence.MinDelay, presence.MaxDelay))
 
 	err := client.SendChatPresence(ctx, chat, types.ChatPresenceComposing, types.ChatPresenceMediaAudio)
 	if err != nil {
 		return
 	}
 
-	recordingDuration := 15 * time.Second
-	time.Sleep(recordingDuration)
+	time.Sleep(presence.Duration)
 
 	client.SendChatPresence(ctx, chat, types.ChatPresencePaused, types.ChatPresenceMediaAudio)
 }
@@ -5,13 +5,13 @@ import (
         "fmt"
         "math/rand"
         "sync"
-        "sync/atomic"
         "time"
 
         "go.mau.fi/whatsmeow"
         "go.mau.fi/whatsmeow/types"
         "go.mau.fi/whatsmeow/types/events"
 
+        "whatsapp-bot/core"
         "whatsapp-bot/utils"
 )
 
@@ -26,10 +26,7 @@ const (
 )
 
 var (
-        autoReadStory    atomic.Bool
-        autoLikeStory    atomic.Bool
-        storyRandomDelay atomic.Bool
-        storyEmojis      = []string{
+        storyEmojis = []string{
                 "🔥", "👍", "😂", "🎉", "💯", "⚡", "✨", "🙏", "👏", "💪",
                 "🤩", "😎", "🤙", "🌟", "🚀", "🗿", "🥳", "😊", "🤗", "😜",
                 "🌈", "🌸", "🌺", "🌻", "🌹", "🌷", "🍀", "🎊", "🎈", "🎁",
@@ -43,49 +40,46 @@ var (
         }
         processedStory = make(map[string]time.Time)
         storyMutex     sync.Mutex
-        storyCooldown  = 3 * time.Second
 )
 
-func init() {
-        autoReadStory.Store(true)
-        autoLikeStory.Store(true)
-        storyRandomDelay.Store(true)
+// storyConfig is base (the main config or a jadibot session's config)
+// with the .contactset overrides of the story's sender applied
+func storyConfig(base core.BotConfig, client *whatsmeow.Client, sender types.JID, phoneNumber string) core.BotConfig {
+        ids, _ := utils.ContactIDs(client, sender)
+        return core.ApplyOverrides(base, "", append(ids, phoneNumber)...)
 }
 
-func GetAutoReadStory() bool {
-        return autoReadStory.Load()
-}
-
-func SetAutoReadStory(val bool) {
-        autoReadStory.Store(val)
-}
-
-func GetAutoLikeStory() bool {
-        return autoLikeStory.Load()
-}
-
-func SetAutoLikeStory(val bool) {
-        autoLikeStory.Store(val)
-}
-
-func GetStoryRandomDelay() bool {
-        return storyRandomDelay.Load()
+func getRandomEmoji() string {
+        return storyEmojis[rand.Intn(len(storyEmojis))]
 }
 
-func SetStoryRandomDelay(val bool) {
-        storyRandomDelay.Store(val)
+func getStoryDelay(random bool) time.Duration {
+        story := core.Runtime().Story
+        if random {
+                return randomDuration(story.MinDelay, story.MaxDelay).Round(time.Second)
+        }
+        return story.NormalDelay
 }
 
-func getRandomEmoji() string {
-        return storyEmojis[rand.Intn(len(storyEmojis))]
+// formatStoryDelay describes the delay waited before viewing a story, for
+// the story logs
+func formatStoryDelay(delay time.Duration, random bool) string {
+        text := fmt.Sprintf("%d ms", delay.Milliseconds())
+        if delay >= time.Second {
+                text = fmt.Sprintf("%g Detik", delay.Round(100*time.Millisecond).Seconds())
+        }
+        if random {
+                text += " (Random)"
+        }
+        return text
 }
 
-func getStoryDelay() time.Duration {
-        if GetStoryRandomDelay() {
-                delaySeconds := rand.Intn(20) + 1
-                return time.Duration(delaySeconds) * time.Second
+// randomDuration picks a duration in [min, max]
+func randomDuration(min, max time.Duration) time.Duration {
+        if max <= min {
+                return min
         }
-        return 1 * time.Second
+        return min + time.Duration(rand.Int63n(int64(max-min)+1))
 }
 
 func formatPhoneNumber(number string) string {
@@ -123,10 +117,6 @@ func isStoryDeleted(msg *events.Message) bool {
 }
 
 func HandleStoryMessage(client *whatsmeow.Client, msg *events.Message) {
-        if !GetAutoReadStory() && !GetAutoLikeStory() {
-                return
-        }
-
         if msg.Info.Chat.Server != types.BroadcastServer {
                 return
         }
@@ -152,12 +142,19 @@ func HandleStoryMessage(client *whatsmeow.Client, msg *events.Message) {
                 phoneNumber = msg.Info.Sender.User
         }
 
-        storyKey := fmt.Sprintf("%s_%s", msg.Info.ID, phoneNumber)
+        // Every main account sees the same story, each with its own config
+        account := botJID.User
+        cfg := storyConfig(core.AccountConfig(account), client, msg.Info.Sender, phoneNumber)
+        if !cfg.AutoReadStory && !cfg.AutoLikeStory {
+                return
+        }
+
+        storyKey := fmt.Sprintf("%s_%s_%s", account, msg.Info.ID, phoneNumber)
         now := time.Now()
 
         storyMutex.Lock()
         if lastTime, exists := processedStory[storyKey]; exists {
-                if now.Sub(lastTime) < storyCooldown {
+                if now.Sub(lastTime) < core.Runtime().Story.Cooldown {
                         storyMutex.Unlock()
                         return
                 }
@@ -167,10 +164,10 @@ func HandleStoryMessage(client *whatsmeow.Client, msg *events.Message) {
 
         go cleanupOldStories()
 
-        go processStory(client, msg, phoneNumber, senderInfo)
+        go processStory(client, msg, phoneNumber, senderInfo, cfg)
 }
 
-func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber string, senderInfo utils.SenderInfo) {
+func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber string, senderInfo utils.SenderInfo, cfg core.BotConfig) {
         ctx := context.Background()
         senderJID := msg.Info.Sender
 
@@ -182,27 +179,26 @@ func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber str
         emoji := ""
         reactionSuccess := false
 
-        if GetAutoLikeStory() {
+        if cfg.AutoLikeStory {
                 emoji = getRandomEmoji()
         }
 
-        delay := getStoryDelay()
+        delay := getStoryDelay(cfg.StoryRandomDelay)
         time.Sleep(delay)
 
-        if GetAutoReadStory() {
+        if cfg.AutoReadStory {
                 client.MarkRead(ctx, []types.MessageID{msg.Info.ID}, msg.Info.Timestamp, msg.Info.Chat, senderJID)
         }
 
-        if GetAutoLikeStory() && emoji != "" {
+        if cfg.AutoLikeStory && emoji != "" {
                 _, err := client.SendMessage(ctx, msg.Info.Chat, client.BuildReaction(msg.Info.Chat, senderJID, msg.Info.ID, emoji))
                 if err == nil {
                         reactionSuccess = true
                 }
         }
 
-        if GetAutoReadStory() || reactionSuccess {
-                loc, _ := time.LoadLocation("Asia/Jakarta")
-                now := time.Now().In(loc)
+        if cfg.AutoReadStory || reactionSuccess {
+                now := core.Runtime().Now()
                 months := []string{
                         "Januari", "Februari", "Maret", "April", "Mei", "Juni",
                         "Juli", "Agustus", "September", "Oktober", "November", "Desember",
@@ -210,7 +206,7 @@ func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber str
                 days := []string{
                         "Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu",
                 }
-                timeStr := fmt.Sprintf("%02d:%02d:%02d WIB", now.Hour(), now.Minute(), now.Second())
+                timeStr := now.Format("15:04:05 MST")
                 dateStr := fmt.Sprintf("%s, %d %s %d", days[now.Weekday()], now.Day(), months[now.Month()-1], now.Year())
 
                 greeting := "Selamat Pagi"
@@ -223,10 +219,7 @@ func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber str
                         greeting = "Selamat Malam"
                 }
 
-                delayMode := "1 Detik"
-                if GetStoryRandomDelay() {
-                        delayMode = fmt.Sprintf("%d Detik (Random)", int(delay.Seconds()))
-                }
+                delayMode := formatStoryDelay(delay, cfg.StoryRandomDelay)
 
                 reactionStr := "-"
                 if reactionSuccess {
@@ -235,6 +228,9 @@ func processStory(client *whatsmeow.Client, msg *events.Message, phoneNumber str
 
                 fmt.Printf("%s├══════════════════════════════════┤%s\n", ColorCyan, ColorReset)
                 fmt.Printf("%s│%s » Status      : %sAktif ✓%s\n", ColorCyan, ColorReset, ColorGreen, ColorReset)
+                if core.MultiAccount() {
+                        fmt.Printf("%s│%s » Akun        : %s%s%s\n", ColorCyan, ColorReset, ColorGreen, clientAccount(client), ColorReset)
+                }
                 fmt.Printf("%s│%s » Tanggal     : %s%s%s\n", ColorCyan, ColorReset, ColorYellow, dateStr, ColorReset)
                 fmt.Printf("%s│%s » Selamat     : %s%s%s\n", ColorCyan, ColorReset, ColorMagenta, greeting, ColorReset)
                 fmt.Printf("%s│%s » Waktu       : %s%s%s\n", ColorCyan, ColorReset, ColorBlue, timeStr, ColorReset)
@@ -2,7 +2,6 @@ package features
 
 import (
         "context"
-        "encoding/json"
         "fmt"
         "os"
         "path/filepath"
@@ -18,12 +17,10 @@ import (
         waLog "go.mau.fi/whatsmeow/util/log"
         "google.golang.org/protobuf/proto"
 
+        "whatsapp-bot/core"
         "whatsapp-bot/utils"
 )
 
-const (
-        JadibotFolder = "Wilykun/jadibot"
-)
 
 // FilteredLogger suppresses verbose SessionCipher MAC verification errors dan retry receipt errors
 type FilteredLogger struct {
@@ -66,8 +63,8 @@ func (f *FilteredLogger) Sub(module string) waLog.Logger {
         return &FilteredLogger{logger: f.logger.Sub(module)}
 }
 
-// Folder structure (per jadibot):
-// Wilykun/jadibot/
+// Folder structure (per jadibot), see core.Paths:
+// <data>/jadibot/
 // ├── 6289681234567/
 // │   ├── 6289681234567.db       (SQLite database)
 // │   ├── 6289681234567.db-shm   (SQLite shared memory)
@@ -85,46 +82,69 @@ type JadibotSession struct {
         PhoneNumber  string
         Client       *whatsmeow.Client
         Container    *sqlstore.Container
-        Connected    bool
         StartTime    time.Time
         OwnerChat    types.JID
-        Reconnecting bool
-        FailCount    int
-        LastFailTime time.Time
+        // QRPairing is true while pairing by QR code instead of pairing code
+        QRPairing bool
+        // ExpiresAt is when the session is removed; zero means never.
+        // ExpiryWarned records that the warning before it was sent.
+        ExpiresAt    time.Time
+        ExpiryWarned bool
+        // Paused sessions keep their database but stay disconnected until
+        // .jadibot resume; they are not reconnected or health checked
+        Paused   bool
+        PausedAt time.Time
+        // StoriesRead and StoriesReacted count stories handled since the
+        // session was loaded
+        StoriesRead    int
+        StoriesReacted int
+        // Label is a free-form name set with .jadibotset <nomor> label
+        Label string
+        // CreatedBy is who ran .jadibot for this session
+        CreatedBy     types.JID
+        LastConnected time.Time
+        LastError     string
+        LastErrorTime time.Time
+        // Settings are this session's own settings, copied from the main
+        // bot's config when the session is created (core.SessionPatch).
+        // Keys it doesn't set (or were reset) follow the main bot's config.
+        Settings core.ConfigPatch
+        // Account is the main account the session was registered with: its
+        // client messages the owner and its config is the base of Settings
+        Account string
 }
 
 type JadibotManager struct {
-        sessions   map[string]*JadibotSession
-        pending    map[string]*JadibotSession
-        mainClient *whatsmeow.Client
-        mu         sync.RWMutex
+        sessions map[string]*JadibotSession
+        pending  map[string]*JadibotSession
+        // mainClients are the clients of the main accounts by number
+        mainClients map[string]*whatsmeow.Client
+        mu          sync.RWMutex
+        // notifier rate limits lifecycle messages to session owners
+        notifier lifecycleNotifier
 }
 
 var jadibotManager = &JadibotManager{
-        sessions: make(map[string]*JadibotSession),
-        pending:  make(map[string]*JadibotSession),
-}
-
-func init() {
-        os.MkdirAll(JadibotFolder, 0o755)
+        sessions:    make(map[string]*JadibotSession),
+        pending:     make(map[string]*JadibotSession),
+        mainClients: make(map[string]*whatsmeow.Client),
 }
 
 // getJadibotFolder - Get subfolder untuk jadibot berdasarkan phone number
-// Struktur: Wilykun/jadibot/{phoneNumber}/
+// Struktur: <data>/jadibot/{phoneNumber}/
 func getJadibotFolder(phoneNumber string) string {
-        return filepath.Join(JadibotFolder, phoneNumber)
+        return core.GetPaths().JadibotSessionDir(phoneNumber)
 }
 
 // getJadibotDBPath - Generate unique database path dengan subfolder per phone number
-// Path: Wilykun/jadibot/{phoneNumber}/{phoneNumber}.db
+// Path: <data>/jadibot/{phoneNumber}/{phoneNumber}.db
 func getJadibotDBPath(phoneNumber string) string {
-        return filepath.Join(getJadibotFolder(phoneNumber), phoneNumber+".db")
+        return core.GetPaths().JadibotDB(phoneNumber)
 }
 
 // getJadibotDBURI - Generate SQLite database URI dengan proper parameters
 func getJadibotDBURI(phoneNumber string) string {
-        dbPath := getJadibotDBPath(phoneNumber)
-        return fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(FULL)&_pragma=wal_autocheckpoint(100)", dbPath)
+        return SessionDBURI(getJadibotDBPath(phoneNumber))
 }
 
 // cleanupJadibotFiles - Hapus seluruh folder jadibot (db + shm + wal) dan subfolder-nya
@@ -134,38 +154,48 @@ func cleanupJadibotFiles(phoneNumber string) {
         fmt.Printf("%s🗑️ Folder jadibot %s dihapus: %s%s\n", ColorYellow, phoneNumber, jadibotFolder, ColorReset)
 }
 
-// getJadibotMetadataPath - Get metadata.json path untuk store jadibot info
-func getJadibotMetadataPath(phoneNumber string) string {
-        return filepath.Join(getJadibotFolder(phoneNumber), "metadata.json")
+func GetJadibotManager() *JadibotManager {
+        return jadibotManager
 }
 
-// saveJadibotMetadata - Save jadibot StartTime ke metadata.json
-func saveJadibotMetadata(phoneNumber string, startTime time.Time) error {
-        metadata := map[string]interface{}{
-                "phoneNumber": phoneNumber,
-                "startTime":   startTime.Unix(),
-                "savedAt":     time.Now().Unix(),
-        }
-        data, _ := json.MarshalIndent(metadata, "", "  ")
-        return os.WriteFile(getJadibotMetadataPath(phoneNumber), data, 0o644)
+// SetMainClient sets the client of a main account, used to message owners
+// about the jadibots registered with that account (pairing, expiry)
+func (jm *JadibotManager) SetMainClient(account string, client *whatsmeow.Client) {
+        jm.mu.Lock()
+        defer jm.mu.Unlock()
+        jm.mainClients[account] = client
 }
 
-// loadJadibotMetadata - Load jadibot StartTime dari metadata.json
-func loadJadibotMetadata(phoneNumber string) time.Time {
-        data, err := os.ReadFile(getJadibotMetadataPath(phoneNumber))
-        if err != nil {
-                return time.Now()
+// mainClientFor returns the client of a main account. Sessions from before
+// multi-account mode have no account and use the default account, or the
+// only one. jm.mu must be held.
+func (jm *JadibotManager) mainClientFor(account string) *whatsmeow.Client {
+        if client, ok := jm.mainClients[account]; ok {
+                return client
         }
-        var metadata map[string]int64
-        json.Unmarshal(data, &metadata)
-        if startUnix, ok := metadata["startTime"]; ok {
-                return time.Unix(startUnix, 0)
+        if client, ok := jm.mainClients[core.DefaultAccount()]; ok {
+                return client
         }
-        return time.Now()
+        if len(jm.mainClients) == 1 {
+                for _, client := range jm.mainClients {
+                        return client
+                }
+        }
+        return nil
 }
 
-func GetJadibotManager() *JadibotManager {
-        return jadibotManager
+// SessionAccount returns the main account a session belongs to
+func (jm *JadibotManager) SessionAccount(phoneNumber string) (string, bool) {
+        jm.mu.RLock()
+        defer jm.mu.RUnlock()
+        session, exists := jm.sessions[phoneNumber]
+        if !exists {
+                session, exists = jm.pending[phoneNumber]
+        }
+        if !exists {
+                return "", false
+        }
+        return session.Account, true
 }
 
 func (jm *JadibotManager) GetAllSessions() []*JadibotSession {
@@ -185,6 +215,104 @@ func (jm *JadibotManager) GetSession(phoneNumber string) *JadibotSession {
         return jm.sessions[phoneNumber]
 }
 
+// SessionConfig returns the effective config of a session: its main
+// account's config with the session's own settings on top
+func (jm *JadibotManager) SessionConfig(phoneNumber string) (core.BotConfig, bool) {
+        jm.mu.RLock()
+        session, exists := jm.sessions[phoneNumber]
+        var settings core.ConfigPatch
+        var account string
+        if exists {
+                settings = session.Settings
+                account = session.Account
+        }
+        jm.mu.RUnlock()
+
+        return settings.Apply(core.AccountConfig(account)), exists
+}
+
+// SessionSettings returns a copy of the session's own settings
+func (jm *JadibotManager) SessionSettings(phoneNumber string) core.ConfigPatch {
+        jm.mu.RLock()
+        defer jm.mu.RUnlock()
+
+        session, exists := jm.sessions[phoneNumber]
+        if !exists {
+                return nil
+        }
+        out := make(core.ConfigPatch, len(session.Settings))
+        for k, v := range session.Settings {
+                out[k] = v
+        }
+        return out
+}
+
+// UpdateSessionSettings merges patch into the session's own settings and
+// persists them. Keys that aren't per-session settings are ignored. It
+// returns the session config before and after.
+func (jm *JadibotManager) UpdateSessionSettings(phoneNumber string, patch core.ConfigPatch) (before, after core.BotConfig, err error) {
+        if err := patch.Normalize(); err != nil {
+                return before, after, err
+        }
+        return jm.editSessionSettings(phoneNumber, func(settings core.ConfigPatch) {
+                for _, field := range core.SessionFields() {
+                        if value, ok := patch[field.Key]; ok {
+                                settings[field.Key] = value
+                        }
+                }
+        })
+}
+
+// ResetSessionSettings removes keys from the session's own settings so they
+// follow the main bot's config again. Without keys every setting is reset.
+func (jm *JadibotManager) ResetSessionSettings(phoneNumber string, keys ...string) (before, after core.BotConfig, err error) {
+        return jm.editSessionSettings(phoneNumber, func(settings core.ConfigPatch) {
+                if len(keys) == 0 {
+                        for key := range settings {
+                                delete(settings, key)
+                        }
+                }
+                for _, key := range keys {
+                        delete(settings, key)
+                }
+        })
+}
+
+// editSessionSettings applies edit to a copy of the session's settings,
+// persists the result and sends presence if auto_online changed
+func (jm *JadibotManager) editSessionSettings(phoneNumber string, edit func(settings core.ConfigPatch)) (before, after core.BotConfig, err error) {
+        jm.mu.Lock()
+        session, exists := jm.sessions[phoneNumber]
+        if !exists {
+                jm.mu.Unlock()
+                return before, after, fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+        }
+
+        main := core.AccountConfig(session.Account)
+        before = session.Settings.Apply(main)
+
+        previous := session.Settings
+        updated := make(core.ConfigPatch, len(previous))
+        for k, v := range previous {
+                updated[k] = v
+        }
+        edit(updated)
+        session.Settings = updated
+        if err := saveJadibotMetadata(session); err != nil {
+                session.Settings = previous
+                jm.mu.Unlock()
+                return before, before, fmt.Errorf("gagal menyimpan settings jadibot: %v", err)
+        }
+        after = updated.Apply(main)
+        client := session.Client
+        jm.mu.Unlock()
+
+        if before.AutoOnline != after.AutoOnline && client != nil && client.IsConnected() {
+                go SendOnlinePresence(client, after.AutoOnline)
+        }
+        return before, after, nil
+}
+
 func (jm *JadibotManager) IsSessionExists(phoneNumber string) bool {
         jm.mu.RLock()
         defer jm.mu.RUnlock()
@@ -192,16 +320,119 @@ func (jm *JadibotManager) IsSessionExists(phoneNumber string) bool {
         return exists
 }
 
-func (jm *JadibotManager) CreatePairingSession(phoneNumber string, ownerChat types.JID, mainClient *whatsmeow.Client) (string, error) {
+// PairingOptions describe a new jadibot registration
+type PairingOptions struct {
+        // Account is the main account the session is registered with
+        Account string
+        // OwnerChat receives pairing and lifecycle messages
+        OwnerChat types.JID
+        // CreatedBy is who ran .jadibot; counted for jadibot.max_per_owner
+        CreatedBy types.JID
+        // QR pairs by QR code images sent to OwnerChat instead of a pairing code
+        QR bool
+        // Duration is how long the session may run; 0 uses
+        // jadibot.default_duration and a negative value means no expiry
+        Duration time.Duration
+}
+
+// CreatePairingSession starts pairing a new jadibot. With opts.QR the QR
+// codes are sent to the owner chat as images and the returned code is
+// empty; otherwise it returns the pairing code.
+//
+// The pending slot is reserved under jm.mu, but connecting and asking for
+// the pairing code run without it so other sessions aren't blocked while
+// WhatsApp answers. The session may be removed meanwhile (.deljadibot,
+// timeout); the steps after that fail or are undone.
+func (jm *JadibotManager) CreatePairingSession(phoneNumber string, mainClient *whatsmeow.Client, opts PairingOptions) (string, error) {
+        session, managed, err := jm.reservePairing(phoneNumber, mainClient, opts)
+        if err != nil {
+                return "", err
+        }
+        client := session.Client
+
+        // abort undoes the pairing after a failed step, unless it was
+        // already removed (or paired) meanwhile
+        abort := func() {
+                jm.mu.Lock()
+                defer jm.mu.Unlock()
+                if jm.pending[phoneNumber] == session {
+                        jm.cancelPairing(phoneNumber)
+                }
+        }
+        // stillPairing reports whether the session wasn't removed meanwhile
+        stillPairing := func() bool {
+                jm.mu.RLock()
+                defer jm.mu.RUnlock()
+                return jm.pending[phoneNumber] == session || jm.sessions[phoneNumber] == session
+        }
+
+        // The QR channel has to exist before connecting
+        var qrChan <-chan whatsmeow.QRChannelItem
+        if session.QRPairing {
+                qrChan, err = client.GetQRChannel(managed.Context())
+                if err != nil {
+                        abort()
+                        return "", fmt.Errorf("gagal membuat QR channel: %v", err)
+                }
+        }
+
+        err = client.ConnectContext(managed.Context())
+        if err != nil {
+                abort()
+                return "", fmt.Errorf("gagal connect: %v", err)
+        }
+
+        if session.QRPairing {
+                if !stillPairing() {
+                        return "", fmt.Errorf("pairing untuk nomor %s dibatalkan", phoneNumber)
+                }
+                go jm.sendPairingQR(managed.Context(), phoneNumber, qrChan)
+                go jm.waitForPairing(managed.Context(), phoneNumber, core.Runtime().Jadibot.PairingTimeout)
+                return "", nil
+        }
+
+        time.Sleep(1 * time.Second)
+
+        code, err := client.PairPhone(managed.Context(), phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+        if err != nil {
+                abort()
+                return "", fmt.Errorf("gagal generate pairing code: %v", err)
+        }
+        if !stillPairing() {
+                return "", fmt.Errorf("pairing untuk nomor %s dibatalkan", phoneNumber)
+        }
+
+        go jm.waitForPairing(managed.Context(), phoneNumber, core.Runtime().Jadibot.PairingTimeout)
+
+        return code, nil
+}
+
+// reservePairing checks the limits and registers a new pending session for
+// phoneNumber with its database and client, all under jm.mu
+func (jm *JadibotManager) reservePairing(phoneNumber string, mainClient *whatsmeow.Client, opts PairingOptions) (*JadibotSession, *ManagedSession, error) {
         jm.mu.Lock()
         defer jm.mu.Unlock()
 
         if _, exists := jm.sessions[phoneNumber]; exists {
-                return "", fmt.Errorf("session untuk nomor %s sudah ada", phoneNumber)
+                return nil, nil, fmt.Errorf("session untuk nomor %s sudah ada", phoneNumber)
         }
 
         if _, exists := jm.pending[phoneNumber]; exists {
-                return "", fmt.Errorf("proses pairing untuk nomor %s sedang berjalan", phoneNumber)
+                return nil, nil, fmt.Errorf("proses pairing untuk nomor %s sedang berjalan", phoneNumber)
+        }
+
+        if err := jm.checkQuota(mainClient, opts.CreatedBy); err != nil {
+                return nil, nil, err
+        }
+
+        useQR := opts.QR
+        duration := opts.Duration
+        if duration == 0 {
+                duration = core.Runtime().Jadibot.DefaultDuration
+        }
+        var expiresAt time.Time
+        if duration > 0 {
+                expiresAt = time.Now().Add(duration)
         }
 
         ctx := context.Background()
@@ -209,7 +440,7 @@ func (jm *JadibotManager) CreatePairingSession(phoneNumber string, ownerChat typ
         jadibotFolder := getJadibotFolder(phoneNumber)
         err := os.MkdirAll(jadibotFolder, 0o755)
         if err != nil {
-                return "", fmt.Errorf("gagal membuat folder jadibot: %v", err)
+                return nil, nil, fmt.Errorf("gagal membuat folder jadibot: %v", err)
         }
 
         baseDBLog := waLog.Stdout("JadibotDB", "ERROR", true)
@@ -218,7 +449,7 @@ func (jm *JadibotManager) CreatePairingSession(phoneNumber string, ownerChat typ
 
         container, err := sqlstore.New(ctx, "sqlite3", dbURI, dbLog)
         if err != nil {
-                return "", fmt.Errorf("gagal membuat database: %v", err)
+                return nil, nil, fmt.Errorf("gagal membuat database: %v", err)
         }
 
         deviceStore := container.NewDevice()
@@ -230,43 +461,35 @@ func (jm *JadibotManager) CreatePairingSession(phoneNumber string, ownerChat typ
                 PhoneNumber: phoneNumber,
                 Client:      client,
                 Container:   container,
-                Connected:   false,
                 StartTime:   time.Now(),
-                OwnerChat:   ownerChat,
-        }
-
-        jm.pending[phoneNumber] = session
-        jm.mainClient = mainClient
-
-        client.AddEventHandler(func(evt interface{}) {
-                jm.handleJadibotEvent(phoneNumber, client, evt)
-        })
-
-        err = client.Connect()
-        if err != nil {
-                delete(jm.pending, phoneNumber)
-                container.Close()
-                jm.deleteJadibotFiles(phoneNumber)
-                return "", fmt.Errorf("gagal connect: %v", err)
+                OwnerChat:   opts.OwnerChat,
+                CreatedBy:   opts.CreatedBy,
+                QRPairing:   useQR,
+                ExpiresAt:   expiresAt,
+                Account:     opts.Account,
         }
 
-        time.Sleep(1 * time.Second)
-
-        code, err := client.PairPhone(ctx, phoneNumber, true, whatsmeow.PairClientChrome, "Chrome (Linux)")
+        // The SessionManager tracks the session from now on; its context
+        // ends the pairing goroutines when the session is removed
+        managed, err := jm.manage(session, StatePairing, "menunggu pairing")
         if err != nil {
-                delete(jm.pending, phoneNumber)
-                client.Disconnect()
                 container.Close()
                 jm.deleteJadibotFiles(phoneNumber)
-                return "", fmt.Errorf("gagal generate pairing code: %v", err)
+                return nil, nil, err
         }
+        jm.pending[phoneNumber] = session
+        jm.mainClients[opts.Account] = mainClient
 
-        go jm.waitForPairing(phoneNumber, 180*time.Second)
-
-        return code, nil
+        client.AddEventHandler(func(evt interface{}) {
+                jm.handleJadibotEvent(phoneNumber, client, evt)
+                GetSessionManager().HandleEvent(phoneNumber, client, evt)
+        })
+        return session, managed, nil
 }
 
-func (jm *JadibotManager) waitForPairing(phoneNumber string, timeout time.Duration) {
+// waitForPairing expires the pairing after timeout. It stops early when
+// the session is removed (ctx) or pairs.
+func (jm *JadibotManager) waitForPairing(ctx context.Context, phoneNumber string, timeout time.Duration) {
         timer := time.NewTimer(timeout)
         defer timer.Stop()
 
@@ -275,23 +498,52 @@ func (jm *JadibotManager) waitForPairing(phoneNumber string, timeout time.Durati
 
         for {
                 select {
+                case <-ctx.Done():
+                        return
                 case <-timer.C:
-                        jm.mu.Lock()
-                        if session, exists := jm.pending[phoneNumber]; exists {
-                                if !session.Connected {
-                                        fmt.Printf("%s⚠️ Pairing timeout untuk jadibot: %s%s\n", ColorYellow, phoneNumber, ColorReset)
-                                        
-                                        // Kirim notif timeout ke owner menggunakan main client
-                                        if jm.mainClient != nil && jm.mainClient.IsConnected() && !session.OwnerChat.IsEmpty() {
-                                                go func(ownerChat types.JID, pairingNumber string) {
-                                                        ctx := context.Background()
-                                                        
-                                                        timeoutMsg := fmt.Sprintf(`❌ *PAIRING JADIBOT TIMEOUT*
+                        jm.expirePairing(phoneNumber)
+                        return
+                case <-ticker.C:
+                        if state, _ := GetSessionManager().State(phoneNumber); state != StatePairing {
+                                return
+                        }
+                }
+        }
+}
+
+// expirePairing cancels a pairing that didn't finish in time and tells the
+// owner. It does nothing if the session already paired.
+func (jm *JadibotManager) expirePairing(phoneNumber string) {
+        jm.mu.Lock()
+        session, exists := jm.pending[phoneNumber]
+        if exists {
+                if state, _ := GetSessionManager().State(phoneNumber); state != StatePairing {
+                        exists = false
+                }
+        }
+        if !exists {
+                jm.mu.Unlock()
+                return
+        }
+        fmt.Printf("%s⚠️ Pairing timeout untuk jadibot: %s%s\n", ColorYellow, phoneNumber, ColorReset)
+        jm.cancelPairing(phoneNumber)
+        mainClient := jm.mainClientFor(session.Account)
+        jm.mu.Unlock()
+
+        // Kirim notif timeout ke owner menggunakan main client
+        if mainClient == nil || !mainClient.IsConnected() || session.OwnerChat.IsEmpty() {
+                return
+        }
+        retryArgs := phoneNumber
+        if session.QRPairing {
+                retryArgs += " qr"
+        }
+        timeoutMsg := fmt.Sprintf(`❌ *PAIRING JADIBOT TIMEOUT*
 
 ═══════════════════════════════════
 
 📱 *Nomor:* %s
-⏰ *Status:* Expired (3 menit)
+⏰ *Status:* Expired (%s)
 🔴 *Kondisi:* Pairing Gagal
 
 ═══════════════════════════════════
@@ -329,56 +581,42 @@ func (jm *JadibotManager) waitForPairing(phoneNumber string, timeout time.Durati
    • *.menu* - Lihat semua command
    • *.deljadibot [nomor]* - Hapus jadibot
 
-═══════════════════════════════════`, pairingNumber, pairingNumber)
-                                                        msg := &waProto.Message{
-                                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                                        Text: proto.String(timeoutMsg),
-                                                                },
-                                                        }
-                                                        _, err := jm.mainClient.SendMessage(ctx, ownerChat, msg)
-                                                        if err != nil {
-                                                                fmt.Printf("%s⚠️ Notif timeout GAGAL: %v%s\n", ColorYellow, err, ColorReset)
-                                                        } else {
-                                                                fmt.Printf("%s✅ Notif timeout terkirim ke owner%s\n", ColorGreen, ColorReset)
-                                                        }
-                                                }(session.OwnerChat, phoneNumber)
-                                                time.Sleep(500 * time.Millisecond)
-                                        }
-                                        
-                                        session.Client.Disconnect()
-                                        if session.Container != nil {
-                                                session.Container.Close()
-                                        }
-                                        delete(jm.pending, phoneNumber)
-                                        jm.deleteJadibotFiles(phoneNumber)
-                                }
-                        }
-                        jm.mu.Unlock()
-                        return
-                case <-ticker.C:
-                        jm.mu.RLock()
-                        session, exists := jm.pending[phoneNumber]
-                        connected := exists && session.Connected
-                        jm.mu.RUnlock()
-
-                        if !exists || connected {
-                                return
-                        }
-                }
+═══════════════════════════════════`, phoneNumber, FormatDuration(core.Runtime().Jadibot.PairingTimeout), retryArgs)
+        msg := &waProto.Message{
+                ExtendedTextMessage: &waProto.ExtendedTextMessage{
+                        Text: proto.String(timeoutMsg),
+                },
+        }
+        _, err := mainClient.SendMessage(context.Background(), session.OwnerChat, msg)
+        if err != nil {
+                fmt.Printf("%s⚠️ Notif timeout GAGAL: %v%s\n", ColorYellow, err, ColorReset)
+        } else {
+                fmt.Printf("%s✅ Notif timeout terkirim ke owner%s\n", ColorGreen, ColorReset)
         }
 }
 
+// cancelPairing drops a pending session and its files. jm.mu must be held.
+func (jm *JadibotManager) cancelPairing(phoneNumber string) {
+        delete(jm.pending, phoneNumber)
+        GetSessionManager().Remove(phoneNumber)
+        jm.deleteJadibotFiles(phoneNumber)
+}
+
 func (jm *JadibotManager) handleJadibotEvent(phoneNumber string, client *whatsmeow.Client, evt interface{}) {
         switch v := evt.(type) {
         case *events.PairSuccess:
                 jm.mu.Lock()
                 var ownerChat types.JID
-                if session, exists := jm.pending[phoneNumber]; exists {
-                        session.Connected = true
+                session, paired := jm.pending[phoneNumber]
+                if paired {
                         ownerChat = session.OwnerChat
+                        // New sessions start from their main account's
+                        // current settings and keep them from then on
+                        session.Settings = core.SessionPatch(core.AccountConfig(session.Account))
+                        session.LastConnected = time.Now()
                         jm.sessions[phoneNumber] = session
                         delete(jm.pending, phoneNumber)
-                        saveJadibotMetadata(phoneNumber, session.StartTime)
+                        saveJadibotMetadata(session)
                 }
                 jm.mu.Unlock()
 
@@ -417,7 +655,7 @@ func (jm *JadibotManager) handleJadibotEvent(phoneNumber string, client *whatsme
 
 🔒 *KEAMANAN & PENYIMPANAN:*
   ✓ Session: Terenkripsi AES-256
-  ✓ Database: Aman di Wilykun/jadibot/
+  ✓ Database: Aman di folder jadibot
   ✓ Backup: Otomatis setiap 5 menit
   ✓ Authenticator: Hybrid encryption
 
@@ -449,15 +687,15 @@ func (jm *JadibotManager) handleJadibotEvent(phoneNumber string, client *whatsme
 
         case *events.Connected:
                 jm.mu.Lock()
-                if session, exists := jm.pending[phoneNumber]; exists {
-                        if client.Store.ID != nil {
-                                session.Connected = true
-                                jm.sessions[phoneNumber] = session
-                                delete(jm.pending, phoneNumber)
-                                fmt.Printf("%s✅ Jadibot terhubung: %s%s\n", ColorGreen, phoneNumber, ColorReset)
-                        }
+                session, activated := jm.pending[phoneNumber]
+                activated = activated && client.Store.ID != nil
+                if activated {
+                        jm.sessions[phoneNumber] = session
+                        delete(jm.pending, phoneNumber)
+                        fmt.Printf("%s✅ Jadibot terhubung: %s%s\n", ColorGreen, phoneNumber, ColorReset)
                 }
                 jm.mu.Unlock()
+                jm.recordConnected(phoneNumber)
 
                 ctx := context.Background()
                 // Multiple presence updates untuk memastikan device aktif
@@ -467,241 +705,74 @@ func (jm *JadibotManager) handleJadibotEvent(phoneNumber string, client *whatsme
                                 time.Sleep(300 * time.Millisecond)
                         }
                 }
-                go utils.CacheAllJoinedGroupsMappings(client)
-
-        case *events.LoggedOut:
-                fmt.Printf("%s⚠️ Jadibot logged out: %s%s\n", ColorYellow, phoneNumber, ColorReset)
-                jm.RemoveSession(phoneNumber)
-
-        case *events.Disconnected:
-                fmt.Printf("%s⚠️ Jadibot disconnected: %s, mencoba reconnect...%s\n", ColorYellow, phoneNumber, ColorReset)
-                go jm.reconnectSession(phoneNumber)
 
+        // Reconnecting, logouts and command routing are done by the
+        // SessionManager, which also drives the owner's notifications
+        // (onStateChange)
         case *events.Message:
-                if v.Info.Chat.Server == types.BroadcastServer {
+                if v.Info.Chat.Server == types.BroadcastServer && !GetSessionManager().AutomationPaused(phoneNumber) {
                         handleJadibotStory(client, v, phoneNumber)
                 }
         }
 }
 
-func (jm *JadibotManager) reconnectSession(phoneNumber string) {
-        jm.mu.Lock()
-        session, exists := jm.sessions[phoneNumber]
-        if !exists || session == nil {
-                jm.mu.Unlock()
-                return
-        }
-
-        if session.Reconnecting {
-                jm.mu.Unlock()
-                return
-        }
-        session.Reconnecting = true
-        jm.mu.Unlock()
-
-        defer func() {
-                jm.mu.Lock()
-                if s, ok := jm.sessions[phoneNumber]; ok {
-                        s.Reconnecting = false
-                }
-                jm.mu.Unlock()
-        }()
-
-        maxRetries := 5
-        retryDelays := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 60 * time.Second}
-
-        for attempt := 1; attempt <= maxRetries; attempt++ {
-                if attempt <= len(retryDelays) {
-                        time.Sleep(retryDelays[attempt-1])
-                } else {
-                        time.Sleep(60 * time.Second)
-                }
-
-                jm.mu.RLock()
-                session, exists = jm.sessions[phoneNumber]
-                jm.mu.RUnlock()
-
-                if !exists || session == nil {
-                        return
-                }
-
-                if session.Client.IsConnected() {
-                        fmt.Printf("%s✅ Jadibot %s sudah terhubung kembali%s\n", ColorGreen, phoneNumber, ColorReset)
+// manage hands a session's client to the SessionManager and moves it to
+// state, or to paused if the session is paused. When the manager gives up
+// on it the jadibot is removed and its owner told why; a pairing is just
+// cancelled.
+func (jm *JadibotManager) manage(session *JadibotSession, state SessionState, reason string) (*ManagedSession, error) {
+        phoneNumber := session.PhoneNumber
+        sm := GetSessionManager()
+        managed := &ManagedSession{
+                ID:        phoneNumber,
+                Account:   session.Account,
+                Client:    session.Client,
+                Container: session.Container,
+                DBPath:    getJadibotDBPath(phoneNumber),
+                OnGiveUp: func(reason string) {
                         jm.mu.Lock()
-                        session.FailCount = 0
-                        jm.mu.Unlock()
-                        return
-                }
-
-                if session.Client.Store.ID == nil {
-                        fmt.Printf("%s❌ Jadibot %s tidak memiliki device ID - MENGHAPUS%s\n", ColorYellow, phoneNumber, ColorReset)
-                        jm.RemoveSession(phoneNumber)
-                        return
-                }
-
-                ctx := context.Background()
-                err := session.Client.Connect()
-                if err != nil {
-                        fmt.Printf("%s⚠️ Gagal reconnect jadibot %s (percobaan %d/%d): %v%s\n", ColorYellow, phoneNumber, attempt, maxRetries, err, ColorReset)
-
-                        jm.mu.Lock()
-                        session.FailCount++
-                        session.LastFailTime = time.Now()
-                        jm.mu.Unlock()
-
-                        if attempt == maxRetries {
-                                fmt.Printf("%s❌ Jadibot %s gagal reconnect setelah %d percobaan - MENGHAPUS%s\n", ColorYellow, phoneNumber, maxRetries, ColorReset)
-                                jm.RemoveSession(phoneNumber)
-                                return
+                        _, pending := jm.pending[phoneNumber]
+                        if pending {
+                                jm.cancelPairing(phoneNumber)
                         }
-                        continue
-                }
-
-                time.Sleep(3 * time.Second)
-
-                if !session.Client.IsConnected() {
-                        fmt.Printf("%s⚠️ Jadibot %s masih tidak terhubung setelah reconnect (percobaan %d/%d)%s\n", ColorYellow, phoneNumber, attempt, maxRetries, ColorReset)
-
-                        jm.mu.Lock()
-                        session.FailCount++
-                        session.LastFailTime = time.Now()
                         jm.mu.Unlock()
-
-                        if attempt == maxRetries {
-                                fmt.Printf("%s❌ Jadibot %s gagal reconnect setelah %d percobaan - MENGHAPUS%s\n", ColorYellow, phoneNumber, maxRetries, ColorReset)
-                                jm.RemoveSession(phoneNumber)
-                                return
+                        if !pending {
+                                jm.removeWithNotice(phoneNumber, reason)
                         }
-                        continue
-                }
-
-                session.Client.SendPresence(ctx, types.PresenceAvailable)
-                fmt.Printf("%s✅ Jadibot %s berhasil reconnect%s\n", ColorGreen, phoneNumber, ColorReset)
-
-                jm.mu.Lock()
-                session.FailCount = 0
-                session.Connected = true
-                jm.mu.Unlock()
-                return
-        }
-}
-
-func (jm *JadibotManager) StartHealthCheck() {
-        ticker := time.NewTicker(60 * time.Second)
-        defer ticker.Stop()
-
-        fmt.Printf("%s🔍 Health check jadibot dimulai (interval: 60 detik)%s\n", ColorCyan, ColorReset)
-
-        for range ticker.C {
-                jm.validateAllSessions()
+                },
+                OnError: func(reason string) {
+                        jm.recordError(phoneNumber, reason)
+                },
+                OnNotice: func(notice Notice) {
+                        jm.notifyOwner(phoneNumber, notice)
+                },
         }
-}
-
-type sessionSnapshot struct {
-        phoneNumber    string
-        hasClient      bool
-        hasDeviceID    bool
-        isConnected    bool
-        isReconnecting bool
-        failCount      int
-        lastFailTime   time.Time
-}
-
-func (jm *JadibotManager) validateAllSessions() {
-        const maxConsecutiveFails = 10
-        const failResetDuration = 5 * time.Minute
-
-        jm.mu.RLock()
-        snapshots := make([]sessionSnapshot, 0, len(jm.sessions))
-        for phoneNumber, session := range jm.sessions {
-                snap := sessionSnapshot{
-                        phoneNumber:    phoneNumber,
-                        hasClient:      session != nil && session.Client != nil,
-                        isReconnecting: session.Reconnecting,
-                        failCount:      session.FailCount,
-                        lastFailTime:   session.LastFailTime,
-                }
-                if snap.hasClient {
-                        snap.hasDeviceID = session.Client.Store != nil && session.Client.Store.ID != nil
-                        snap.isConnected = session.Client.IsConnected()
-                }
-                snapshots = append(snapshots, snap)
+        if err := sm.Add(managed); err != nil {
+                return nil, err
         }
-        jm.mu.RUnlock()
-
-        var invalidSessions []string
-
-        for _, snap := range snapshots {
-                if !snap.hasClient {
-                        invalidSessions = append(invalidSessions, snap.phoneNumber)
-                        continue
-                }
-
-                if !snap.hasDeviceID {
-                        fmt.Printf("%s⚠️ [Health Check] Jadibot %s tidak memiliki device ID%s\n", ColorYellow, snap.phoneNumber, ColorReset)
-                        invalidSessions = append(invalidSessions, snap.phoneNumber)
-                        continue
-                }
-
-                if snap.isReconnecting {
-                        continue
-                }
-
-                if time.Since(snap.lastFailTime) > failResetDuration && snap.failCount > 0 {
-                        jm.mu.Lock()
-                        if s, ok := jm.sessions[snap.phoneNumber]; ok {
-                                s.FailCount = 0
-                        }
-                        jm.mu.Unlock()
-                }
-
-                if !snap.isConnected {
-                        jm.mu.Lock()
-                        session, exists := jm.sessions[snap.phoneNumber]
-                        if !exists {
-                                jm.mu.Unlock()
-                                continue
-                        }
-                        session.FailCount++
-                        session.LastFailTime = time.Now()
-                        currentFailCount := session.FailCount
-                        jm.mu.Unlock()
-
-                        if currentFailCount >= maxConsecutiveFails {
-                                fmt.Printf("%s❌ [Health Check] Jadibot %s gagal terhubung %d kali berturut-turut - MENGHAPUS%s\n", ColorYellow, snap.phoneNumber, currentFailCount, ColorReset)
-                                invalidSessions = append(invalidSessions, snap.phoneNumber)
-                                continue
-                        }
-
-                        fmt.Printf("%s⚠️ [Health Check] Jadibot %s tidak terhubung (fail count: %d/%d), memulai reconnect...%s\n", ColorYellow, snap.phoneNumber, currentFailCount, maxConsecutiveFails, ColorReset)
-                        go jm.reconnectSession(snap.phoneNumber)
-                } else {
-                        jm.mu.Lock()
-                        if s, ok := jm.sessions[snap.phoneNumber]; ok && s.FailCount > 0 {
-                                s.FailCount = 0
-                        }
-                        jm.mu.Unlock()
-                }
+        if session.Paused {
+                sm.SetPaused(phoneNumber, true)
+        } else {
+                sm.SetState(phoneNumber, state, reason)
         }
+        return managed, nil
+}
 
-        for _, phoneNumber := range invalidSessions {
-                fmt.Printf("%s🗑️ [Health Check] Menghapus jadibot tidak valid: %s%s\n", ColorYellow, phoneNumber, ColorReset)
-                jm.RemoveSession(phoneNumber)
+// onStateChange tells owners when their jadibot drops and comes back
+func (jm *JadibotManager) onStateChange(change StateChange) {
+        if change.Primary {
+                return
         }
-
-        jm.cleanupOrphanedFiles()
-
-        jm.mu.RLock()
-        activeCount := len(jm.sessions)
-        jm.mu.RUnlock()
-
-        if len(invalidSessions) > 0 {
-                fmt.Printf("%s📊 [Health Check] Selesai - %d jadibot aktif, %d dihapus%s\n", ColorCyan, activeCount, len(invalidSessions), ColorReset)
+        switch {
+        case change.From == StateConnected && change.To == StateBackoff:
+                jm.notifyLifecycle(change.ID, eventDisconnected, change.Reason)
+        case change.To == StateConnected && (change.From == StateBackoff || change.From == StateConnecting):
+                jm.notifyLifecycle(change.ID, eventReconnected, "koneksi pulih setelah terputus")
         }
 }
 
 func (jm *JadibotManager) cleanupOrphanedFiles() {
-        files, err := os.ReadDir(JadibotFolder)
+        files, err := os.ReadDir(core.GetPaths().JadibotDir())
         if err != nil {
                 return
         }
@@ -727,29 +798,72 @@ func (jm *JadibotManager) cleanupOrphanedFiles() {
                 }
 
                 phoneNumber := strings.TrimSuffix(fileName, ".db")
+                if activeSessions[phoneNumber] {
+                        continue
+                }
 
-                if !activeSessions[phoneNumber] {
-                        ctx := context.Background()
-                        baseDBLog := waLog.Stdout("JadibotDB", "ERROR", true)
-                        dbLog := &FilteredLogger{logger: baseDBLog}
-                        dbURI := getJadibotDBURI(phoneNumber)
-
-                        container, err := sqlstore.New(ctx, "sqlite3", dbURI, dbLog)
-                        if err != nil {
-                                jm.deleteJadibotFiles(phoneNumber)
-                                continue
-                        }
+                // A flat <jadibot>/<nomor>.db is left over from before every
+                // jadibot had its own folder. A valid one is moved into its
+                // folder (loaded on the next start); anything else is deleted.
+                strayPath := filepath.Join(core.GetPaths().JadibotDir(), fileName)
+                if reason := checkStrayDB(strayPath); reason != "" {
+                        removeStrayDB(phoneNumber, strayPath)
+                        ownerChat, mainClient := jm.ownerOf(phoneNumber)
+                        jm.sendRemovedNotice(mainClient, phoneNumber, ownerChat, reason)
+                        continue
+                }
+                if _, err := os.Stat(getJadibotDBPath(phoneNumber)); os.IsNotExist(err) {
+                        migrateStrayDB(phoneNumber, strayPath)
+                }
+        }
+}
 
-                        deviceStore, err := container.GetFirstDevice(ctx)
-                        if err != nil || deviceStore.ID == nil {
-                                container.Close()
-                                jm.deleteJadibotFiles(phoneNumber)
-                                continue
-                        }
+// checkStrayDB opens a database outside the jadibot folders and returns why
+// it is unusable, or "" if it holds a paired device
+func checkStrayDB(path string) string {
+        ctx := context.Background()
+        baseDBLog := waLog.Stdout("JadibotDB", "ERROR", true)
+        dbLog := &FilteredLogger{logger: baseDBLog}
+
+        container, err := sqlstore.New(ctx, "sqlite3", SessionDBURI(path), dbLog)
+        if err != nil {
+                return fmt.Sprintf("database session tidak terpakai dan tidak bisa dibuka: %v", err)
+        }
+        defer container.Close()
 
-                        container.Close()
+        deviceStore, err := container.GetFirstDevice(ctx)
+        if err != nil || deviceStore.ID == nil {
+                return "database session tidak terpakai dan tidak berisi perangkat yang valid"
+        }
+        return ""
+}
+
+// removeStrayDB deletes a database outside the jadibot folders with its
+// WAL files
+func removeStrayDB(phoneNumber, path string) {
+        for _, file := range []string{path, path + "-wal", path + "-shm"} {
+                if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+                        fmt.Printf("%s⚠️ Gagal menghapus %s: %v%s\n", ColorYellow, file, err, ColorReset)
                 }
         }
+        fmt.Printf("%s🗑️ Database jadibot %s di luar foldernya dihapus: %s%s\n", ColorYellow, phoneNumber, path, ColorReset)
+}
+
+// migrateStrayDB moves a valid database outside the jadibot folders (and its
+// WAL files) into the jadibot's folder
+func migrateStrayDB(phoneNumber, path string) {
+        target := getJadibotDBPath(phoneNumber)
+        if err := os.MkdirAll(getJadibotFolder(phoneNumber), 0o755); err != nil {
+                fmt.Printf("%s⚠️ Gagal membuat folder jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+                return
+        }
+        for _, suffix := range []string{"", "-wal", "-shm"} {
+                if err := os.Rename(path+suffix, target+suffix); err != nil && !os.IsNotExist(err) {
+                        fmt.Printf("%s⚠️ Gagal memindahkan %s: %v%s\n", ColorYellow, path+suffix, err, ColorReset)
+                        return
+                }
+        }
+        fmt.Printf("%s📦 Database jadibot %s dipindah ke %s (dimuat saat bot dimulai ulang)%s\n", ColorGreen, phoneNumber, target, ColorReset)
 }
 
 func (jm *JadibotManager) RemoveSession(phoneNumber string) error {
@@ -758,24 +872,23 @@ func (jm *JadibotManager) RemoveSession(phoneNumber string) error {
 
         session, exists := jm.sessions[phoneNumber]
         if !exists {
-                if pendingSession, pendingExists := jm.pending[phoneNumber]; pendingExists {
-                        pendingSession.Client.Disconnect()
-                        if pendingSession.Container != nil {
-                                pendingSession.Container.Close()
-                        }
-                        delete(jm.pending, phoneNumber)
-                        jm.deleteJadibotFiles(phoneNumber)
+                if _, pendingExists := jm.pending[phoneNumber]; pendingExists {
+                        jm.cancelPairing(phoneNumber)
                         return nil
                 }
                 return fmt.Errorf("session tidak ditemukan: %s", phoneNumber)
         }
 
-        if session.Client != nil {
-                session.Client.Disconnect()
-        }
-
-        if session.Container != nil {
-                session.Container.Close()
+        // The SessionManager disconnects and closes the sessions it runs
+        if sm := GetSessionManager(); sm.Get(phoneNumber) != nil {
+                sm.Remove(phoneNumber)
+        } else {
+                if session.Client != nil {
+                        session.Client.Disconnect()
+                }
+                if session.Container != nil {
+                        session.Container.Close()
+                }
         }
 
         delete(jm.sessions, phoneNumber)
@@ -792,7 +905,14 @@ func (jm *JadibotManager) deleteJadibotFiles(phoneNumber string) {
 func (jm *JadibotManager) LoadExistingSessions() {
         ctx := context.Background()
 
-        files, err := os.ReadDir(JadibotFolder)
+        // Connections are checked by the SessionManager, jadibot-only
+        // upkeep runs with it
+        sm := GetSessionManager()
+        sm.OnHealthCheck(jm.checkExpiry)
+        sm.OnHealthCheck(jm.cleanupOrphanedFiles)
+        SubscribeState(jm.onStateChange)
+
+        files, err := os.ReadDir(core.GetPaths().JadibotDir())
         if err != nil {
                 fmt.Printf("%s⚠️ Gagal membaca folder jadibot: %v%s\n", ColorYellow, err, ColorReset)
                 return
@@ -819,7 +939,7 @@ func (jm *JadibotManager) LoadExistingSessions() {
                                 container, err := sqlstore.New(ctx, "sqlite3", dbURI, dbLog)
                                 if err != nil {
                                         fmt.Printf("%s⚠️ Gagal load database jadibot %s: %v - MENGHAPUS%s\n", ColorYellow, phoneNumber, err, ColorReset)
-                                        jm.deleteJadibotFiles(phoneNumber)
+                                        jm.deleteFilesWithNotice(phoneNumber, fmt.Sprintf("database session rusak saat bot dimulai: %v", err))
                                         return
                                 }
 
@@ -827,14 +947,14 @@ func (jm *JadibotManager) LoadExistingSessions() {
                                 if err != nil {
                                         fmt.Printf("%s⚠️ Gagal get device jadibot %s: %v - MENGHAPUS%s\n", ColorYellow, phoneNumber, err, ColorReset)
                                         container.Close()
-                                        jm.deleteJadibotFiles(phoneNumber)
+                                        jm.deleteFilesWithNotice(phoneNumber, fmt.Sprintf("data perangkat tidak bisa dibaca saat bot dimulai: %v", err))
                                         return
                                 }
 
                                 if deviceStore.ID == nil {
                                         fmt.Printf("%s⚠️ Jadibot %s belum terhubung/tidak valid - MENGHAPUS%s\n", ColorYellow, phoneNumber, ColorReset)
                                         container.Close()
-                                        jm.deleteJadibotFiles(phoneNumber)
+                                        jm.deleteFilesWithNotice(phoneNumber, "session belum pernah selesai pairing")
                                         return
                                 }
 
@@ -842,24 +962,38 @@ func (jm *JadibotManager) LoadExistingSessions() {
                                 clientLog := &FilteredLogger{logger: baseClientLog}
                                 client := whatsmeow.NewClient(deviceStore, clientLog)
 
-                                startTime := loadJadibotMetadata(phoneNumber)
                                 session := &JadibotSession{
                                         PhoneNumber: phoneNumber,
                                         Client:      client,
                                         Container:   container,
-                                        Connected:   false,
-                                        StartTime:   startTime,
                                 }
+                                loadJadibotMetadata(phoneNumber).applyTo(session)
 
                                 client.AddEventHandler(func(evt interface{}) {
                                         jm.handleJadibotEvent(phoneNumber, client, evt)
+                                        GetSessionManager().HandleEvent(phoneNumber, client, evt)
                                 })
+                                if _, err := jm.manage(session, StateConnecting, "memuat session"); err != nil {
+                                        fmt.Printf("%s⚠️ Jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+                                        container.Close()
+                                        return
+                                }
+
+                                // Paused sessions are kept but not connected
+                                // until .jadibot resume
+                                if session.Paused {
+                                        jm.mu.Lock()
+                                        jm.sessions[phoneNumber] = session
+                                        jm.mu.Unlock()
+                                        fmt.Printf("%s⏸️ Jadibot %s dijeda - tidak dihubungkan%s\n", ColorYellow, phoneNumber, ColorReset)
+                                        return
+                                }
 
                                 err = client.Connect()
                                 if err != nil {
                                         fmt.Printf("%s⚠️ Gagal connect jadibot %s: %v - MENGHAPUS%s\n", ColorYellow, phoneNumber, err, ColorReset)
-                                        container.Close()
-                                        jm.deleteJadibotFiles(phoneNumber)
+                                        GetSessionManager().Remove(phoneNumber)
+                                        jm.deleteFilesWithNotice(phoneNumber, fmt.Sprintf("gagal terhubung saat bot dimulai: %v", err))
                                         return
                                 }
 
@@ -868,14 +1002,22 @@ func (jm *JadibotManager) LoadExistingSessions() {
 
                                 if client.Store.ID == nil || !client.IsConnected() {
                                         fmt.Printf("%s⚠️ Jadibot %s tidak dapat terhubung - MENGHAPUS%s\n", ColorYellow, phoneNumber, ColorReset)
-                                        client.Disconnect()
-                                        container.Close()
-                                        jm.deleteJadibotFiles(phoneNumber)
+                                        GetSessionManager().Remove(phoneNumber)
+                                        jm.deleteFilesWithNotice(phoneNumber, "tidak dapat terhubung saat bot dimulai (kemungkinan logout dari HP)")
                                         return
                                 }
 
                                 jm.mu.Lock()
-                                session.Connected = true
+                                session.LastConnected = time.Now()
+                                // Sessions saved before per-session settings
+                                // existed take a copy of the main config once
+                                if session.Settings == nil {
+                                        session.Settings = core.SessionPatch(core.AccountConfig(session.Account))
+                                }
+                                // Also upgrades older metadata.json files
+                                if err := saveJadibotMetadata(session); err != nil {
+                                        fmt.Printf("%s⚠️ Gagal menyimpan metadata jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+                                }
                                 jm.sessions[phoneNumber] = session
                                 jm.mu.Unlock()
 
@@ -886,10 +1028,9 @@ func (jm *JadibotManager) LoadExistingSessions() {
         }
 
         wg.Wait()
-        go jm.StartHealthCheck()
 }
 
-func processJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNumber string, senderPhone string, senderInfo utils.SenderInfo) {
+func processJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNumber string, senderPhone string, senderInfo utils.SenderInfo, cfg core.BotConfig) {
         ctx := context.Background()
         senderJID := msg.Info.Sender
 
@@ -902,17 +1043,16 @@ func processJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNum
         reactionSuccess := false
 
         // Generate emoji HANYA jika autoLikeStory aktif
-        if GetAutoLikeStory() {
+        if cfg.AutoLikeStory {
                 emoji = getRandomEmoji()
         }
 
-        // 1️⃣ Delay DULU (sama seperti bot utama) - menggunakan config random 1-20 detik
-        delay := getStoryDelay()
-        delaySeconds := delay.Seconds()
+        // 1️⃣ Delay DULU (sama seperti bot utama) - story.normal_delay atau random story.min_delay-max_delay
+        delay := getStoryDelay(cfg.StoryRandomDelay)
         time.Sleep(delay)
 
         // 2️⃣ Send reaction PRIORITAS! HANYA jika autoLikeStory aktif DAN emoji ada (SETELAH delay, SEBELUM read)
-        if GetAutoLikeStory() && emoji != "" {
+        if cfg.AutoLikeStory && emoji != "" {
                 _, err := client.SendMessage(ctx, msg.Info.Chat, client.BuildReaction(msg.Info.Chat, senderJID, msg.Info.ID, emoji))
                 if err == nil {
                         reactionSuccess = true
@@ -923,15 +1063,16 @@ func processJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNum
         }
 
         // 3️⃣ MarkRead HANYA jika autoReadStory aktif (SETELAH reaction!)
-        if GetAutoReadStory() {
-                client.MarkRead(ctx, []types.MessageID{msg.Info.ID}, msg.Info.Timestamp, msg.Info.Chat, senderJID)
+        readSuccess := false
+        if cfg.AutoReadStory {
+                readSuccess = client.MarkRead(ctx, []types.MessageID{msg.Info.ID}, msg.Info.Timestamp, msg.Info.Chat, senderJID) == nil
         }
+        GetJadibotManager().countStory(phoneNumber, readSuccess, reactionSuccess)
 
         // Print hasil HANYA jika ada action yang berhasil (read atau reaction)
-        if (GetAutoReadStory() || reactionSuccess) {
-                loc, _ := time.LoadLocation("Asia/Jakarta")
-                now := time.Now().In(loc)
-                timeStr := fmt.Sprintf("%02d:%02d:%02d WIB", now.Hour(), now.Minute(), now.Second())
+        if (cfg.AutoReadStory || reactionSuccess) {
+                now := core.Runtime().Now()
+                timeStr := now.Format("15:04:05 MST")
 
                 reactionStr := "-"
                 if reactionSuccess {
@@ -939,12 +1080,7 @@ func processJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNum
                 }
 
                 // Format delay info sesuai config
-                delayStr := "Disabled"
-                if GetStoryRandomDelay() {
-                        delayStr = fmt.Sprintf("%.0f Detik (Random)", delaySeconds)
-                } else {
-                        delayStr = "1 Detik"
-                }
+                delayStr := formatStoryDelay(delay, cfg.StoryRandomDelay)
 
                 fmt.Printf("%s├══════════════════════════════════┤%s\n", ColorCyan, ColorReset)
                 fmt.Printf("%s│%s » Jadibot    : %s%s%s\n", ColorCyan, ColorReset, ColorMagenta, phoneNumber, ColorReset)
@@ -998,12 +1134,18 @@ func handleJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNumb
                 senderPhone = msg.Info.Sender.User
         }
 
+        base, _ := GetJadibotManager().SessionConfig(phoneNumber)
+        cfg := storyConfig(base, client, msg.Info.Sender, senderPhone)
+        if !cfg.AutoReadStory && !cfg.AutoLikeStory {
+                return
+        }
+
         storyKey := fmt.Sprintf("jadibot_%s_%s_%s", phoneNumber, msg.Info.ID, senderPhone)
         now := time.Now()
 
         storyMutex.Lock()
         if lastTime, exists := processedStory[storyKey]; exists {
-                if now.Sub(lastTime) < storyCooldown {
+                if now.Sub(lastTime) < core.Runtime().Story.Cooldown {
                         storyMutex.Unlock()
                         return
                 }
@@ -1011,23 +1153,31 @@ func handleJadibotStory(client *whatsmeow.Client, msg *events.Message, phoneNumb
         processedStory[storyKey] = now
         storyMutex.Unlock()
 
-        go processJadibotStory(client, msg, phoneNumber, senderPhone, senderInfo)
+        go processJadibotStory(client, msg, phoneNumber, senderPhone, senderInfo, cfg)
 }
 
-func HandleJadibotCommand(client *whatsmeow.Client, chat types.JID, messageID string, sender types.JID, args string) {
+// HandleJadibotCommand - Daftarkan jadibot dengan kode pairing, atau QR code
+// (dikirim sebagai gambar ke chat ini) jika opts.QR. OwnerChat dan CreatedBy
+// diisi dari chat dan sender.
+func HandleJadibotCommand(client *whatsmeow.Client, chat types.JID, messageID string, sender types.JID, args string, opts PairingOptions) {
+        opts.OwnerChat = chat
+        opts.CreatedBy = sender
+        useQR := opts.QR
+
         ctx := context.Background()
 
         if args == "" {
                 helpMsg := `📱 *JADIBOT COMMAND*
 
 Cara pakai:
-*.jadibot 6289xxxxxxxxx*
+*.jadibot 6289xxxxxxxxx* (kode pairing)
+*.jadibot 6289xxxxxxxxx qr* (scan QR code)
 
 Contoh:
 *.jadibot 6289681234567*
 
 ⚠️ *Catatan:*
-• Nomor harus diawali 6289
+• Nomor ditulis dengan kode negara (62xxx, 60xxx, +1xxx)
 • Fitur: Auto Read Story & Auto Reaction
 • Ketik *.listjadibot* untuk melihat daftar
 • Ketik *.deljadibot 6289xxx* untuk menghapus`
@@ -1045,28 +1195,18 @@ Contoh:
                 return
         }
 
-        phoneNumber := strings.TrimSpace(args)
-        phoneNumber = strings.ReplaceAll(phoneNumber, "+", "")
-        phoneNumber = strings.ReplaceAll(phoneNumber, "-", "")
-        phoneNumber = strings.ReplaceAll(phoneNumber, " ", "")
-
-        if !strings.HasPrefix(phoneNumber, "62") {
-                if strings.HasPrefix(phoneNumber, "0") {
-                        phoneNumber = "62" + phoneNumber[1:]
-                }
-        }
+        phone, err := utils.ParsePhoneNumber(args, core.Runtime().DefaultRegion)
+        if err != nil {
+                errorMsg := fmt.Sprintf(`❌ *Format Nomor Salah!*
 
-        if !strings.HasPrefix(phoneNumber, "6289") && !strings.HasPrefix(phoneNumber, "6281") && !strings.HasPrefix(phoneNumber, "6282") && !strings.HasPrefix(phoneNumber, "6283") && !strings.HasPrefix(phoneNumber, "6285") && !strings.HasPrefix(phoneNumber, "6287") && !strings.HasPrefix(phoneNumber, "6288") {
-                errorMsg := `❌ *Format Nomor Salah!*
+%v
 
-Nomor harus diawali dengan kode Indonesia:
-• 6281xxxxxxxxx
-• 6282xxxxxxxxx
-• 6285xxxxxxxxx
-• 6289xxxxxxxxx
-• dll
+Tulis nomor dengan kode negara:
+• 6281xxxxxxxxx (Indonesia)
+• 60123xxxxxxx (Malaysia)
+• +1 555 xxx xxxx
 
-Contoh: *.jadibot 6289681234567*`
+Contoh: *.jadibot 6289681234567*`, err)
 
                 replyMsg := &waProto.Message{
                         ExtendedTextMessage: &waProto.ExtendedTextMessage{
@@ -1080,9 +1220,16 @@ Contoh: *.jadibot 6289681234567*`
                 client.SendMessage(ctx, chat, replyMsg)
                 return
         }
+        phoneNumber := phone.Number
+
+        pairingLabel := "pairing code"
+        if useQR {
+                pairingLabel = "QR code"
+        }
 
-        if len(phoneNumber) < 10 || len(phoneNumber) > 15 {
-                errorMsg := "❌ *Nomor tidak valid!*\n\nPanjang nomor harus 10-15 digit."
+        jm := GetJadibotManager()
+        if jm.IsSessionExists(phoneNumber) {
+                errorMsg := fmt.Sprintf("❌ *Nomor %s sudah terdaftar sebagai jadibot!*\n\nKetik *.listjadibot* untuk melihat daftar.", phoneNumber)
                 replyMsg := &waProto.Message{
                         ExtendedTextMessage: &waProto.ExtendedTextMessage{
                                 Text: proto.String(errorMsg),
@@ -1095,10 +1242,8 @@ Contoh: *.jadibot 6289681234567*`
                 client.SendMessage(ctx, chat, replyMsg)
                 return
         }
-
-        jm := GetJadibotManager()
-        if jm.IsSessionExists(phoneNumber) {
-                errorMsg := fmt.Sprintf("❌ *Nomor %s sudah terdaftar sebagai jadibot!*\n\nKetik *.listjadibot* untuk melihat daftar.", phoneNumber)
+        if managed := GetSessionManager().Get(phoneNumber); managed != nil && managed.Primary {
+                errorMsg := fmt.Sprintf("❌ *Nomor %s adalah akun bot utama!*\n\nAkun bot utama tidak bisa didaftarkan sebagai jadibot.", phoneNumber)
                 replyMsg := &waProto.Message{
                         ExtendedTextMessage: &waProto.ExtendedTextMessage{
                                 Text: proto.String(errorMsg),
@@ -1115,17 +1260,18 @@ Contoh: *.jadibot 6289681234567*`
         waitMsg := fmt.Sprintf(`⏳ *JADIBOT - LOADING...*
 
 📱 *Nomor Tujuan:* %s
+🌍 *Negara:* %s
 
 *Status Proses:*
-⏳ Membuat pairing code...
+⏳ Membuat %s...
 ☐ Menunggu pairing
 ☐ Verifikasi koneksi
 ☐ Aktivasi fitur
 
 ⏰ *Estimasi:* ~30 detik
-💾 *Lokasi Data:* Wilykun/jadibot/
+💾 *Lokasi Data:* Folder jadibot
 
-_Mohon tunggu sebentar..._`, phoneNumber)
+_Mohon tunggu sebentar..._`, phoneNumber, phone.Country(), pairingLabel)
         replyMsg := &waProto.Message{
                 ExtendedTextMessage: &waProto.ExtendedTextMessage{
                         Text: proto.String(waitMsg),
@@ -1137,9 +1283,9 @@ _Mohon tunggu sebentar..._`, phoneNumber)
         }
         client.SendMessage(ctx, chat, replyMsg)
 
-        code, err := jm.CreatePairingSession(phoneNumber, chat, client)
+        code, err := jm.CreatePairingSession(phoneNumber, client, opts)
         if err != nil {
-                errorMsg := fmt.Sprintf("❌ *Gagal membuat pairing code!*\n\nError: %v", err)
+                errorMsg := fmt.Sprintf("❌ *Gagal membuat %s!*\n\nError: %v", pairingLabel, err)
                 replyMsg := &waProto.Message{
                         ExtendedTextMessage: &waProto.ExtendedTextMessage{
                                 Text: proto.String(errorMsg),
@@ -1153,11 +1299,37 @@ _Mohon tunggu sebentar..._`, phoneNumber)
                 return
         }
 
+        if useQR {
+                qrMsg := fmt.Sprintf(`✅ *QR CODE SIAP!*
+
+📱 *Nomor Tujuan:* %s
+🌍 *Negara:* %s
+
+QR code dikirim sebagai gambar di chat ini dan diganti otomatis setiap
+kali kedaluwarsa. Scan dari WhatsApp nomor tujuan:
+⋮ → "Perangkat Tertaut" → "Tautkan Perangkat"
+
+⏱️ *Batas waktu:* %s`, phoneNumber, phone.Country(), FormatDuration(core.Runtime().Jadibot.PairingTimeout))
+                replyMsg = &waProto.Message{
+                        ExtendedTextMessage: &waProto.ExtendedTextMessage{
+                                Text: proto.String(qrMsg),
+                                ContextInfo: &waProto.ContextInfo{
+                                        StanzaID:    proto.String(messageID),
+                                        Participant: proto.String(sender.String()),
+                                },
+                        },
+                }
+                client.SendMessage(ctx, chat, replyMsg)
+                fmt.Printf("%s🤖 Jadibot QR pairing dimulai untuk %s%s\n", ColorGreen, phoneNumber, ColorReset)
+                return
+        }
+
         successMsg := fmt.Sprintf(`✅ *PAIRING CODE BERHASIL DIBUAT!*
 
 ═══════════════════════════════════
 
 📱 *Nomor Tujuan:* %s
+🌍 *Negara:* %s
 🔑 *Kode Pairing:* *%s*
 
 ═══════════════════════════════════
@@ -1190,7 +1362,7 @@ _Mohon tunggu sebentar..._`, phoneNumber)
 ═══════════════════════════════════
 💾 *Data Keamanan:*
    • Database: Terenkripsi & Aman
-   • Session: Disimpan di Wilykun/jadibot/
+   • Session: Disimpan di folder jadibot
    • Backup: Otomatis setiap 5 menit
 
 📌 *Bantuan:*
@@ -1198,7 +1370,7 @@ _Mohon tunggu sebentar..._`, phoneNumber)
    • Ketik *.menu* - Menu lengkap
    • Ketik *.deljadibot [nomor]* - Hapus
 
-═══════════════════════════════════`, phoneNumber, code, code)
+═══════════════════════════════════`, phoneNumber, phone.Country(), code, code)
 
         replyMsg = &waProto.Message{
                 ExtendedTextMessage: &waProto.ExtendedTextMessage{
@@ -1214,10 +1386,42 @@ _Mohon tunggu sebentar..._`, phoneNumber)
         fmt.Printf("%s🤖 Jadibot pairing code generated for %s: %s%s\n", ColorGreen, phoneNumber, code, ColorReset)
 }
 
-func HandleListJadibotCommand(client *whatsmeow.Client, chat types.JID, messageID string, sender types.JID) {
+// describeSessionSettings - Ringkasan setting per-session, contoh
+// "online ON, likestory OFF (utama)"; (utama) = mengikuti bot utama
+func describeSessionSettings(jm *JadibotManager, phoneNumber string) string {
+        cfg, _ := jm.SessionConfig(phoneNumber)
+        own := jm.SessionSettings(phoneNumber)
+
+        var parts []string
+        for _, field := range core.SessionFields() {
+                part := fmt.Sprintf("%s %s", field.Command, field.Format(field.Get(cfg)))
+                if _, ok := own[field.Key]; !ok {
+                        part += " (utama)"
+                }
+                parts = append(parts, part)
+        }
+        return strings.Join(parts, ", ")
+}
+
+// HandleListJadibotCommand - onlyNumber membatasi daftar ke satu jadibot (untuk user jadibot), kosong = semua
+func HandleListJadibotCommand(client *whatsmeow.Client, chat types.JID, messageID string, sender types.JID, onlyNumber string) {
         ctx := context.Background()
         jm := GetJadibotManager()
         sessions := jm.GetAllSessions()
+        // Each main account only lists the jadibots registered with it
+        account := ""
+        if core.MultiAccount() {
+                account = clientAccount(client)
+        }
+        if onlyNumber != "" || account != "" {
+                var filtered []*JadibotSession
+                for _, session := range sessions {
+                        if (onlyNumber == "" || session.PhoneNumber == onlyNumber) && (account == "" || session.Account == account) {
+                                filtered = append(filtered, session)
+                        }
+                }
+                sessions = filtered
+        }
 
         if len(sessions) == 0 {
                 noSessionMsg := `📋 *DAFTAR JADIBOT*
@@ -1244,8 +1448,10 @@ Ketik *.jadibot 6289xxxxxxxxx* untuk menambahkan.`
         
         for i, session := range sessions {
                 status := "🔴 Offline"
-                if session.Connected && session.Client.IsConnected() {
-                        status = "🟢 Online"
+                if session.Paused {
+                        status = fmt.Sprintf("⏸️ Dijeda sejak %s", FormatStartDateTime(session.PausedAt))
+                } else if state, ok := GetSessionManager().State(session.PhoneNumber); ok {
+                        status = state.Label()
                 }
 
                 // Format tanggal dan waktu terhubung
@@ -1255,15 +1461,25 @@ Ketik *.jadibot 6289xxxxxxxxx* untuk menambahkan.`
                 uptime := time.Since(session.StartTime)
                 uptimeStr := FormatDuration(uptime)
 
-                listMsg += fmt.Sprintf("*%d.* 📱 %s\n", i+1, session.PhoneNumber)
+                if session.Label != "" {
+                        listMsg += fmt.Sprintf("*%d.* 📱 %s (%s)\n", i+1, session.PhoneNumber, session.Label)
+                } else {
+                        listMsg += fmt.Sprintf("*%d.* 📱 %s\n", i+1, session.PhoneNumber)
+                }
                 listMsg += fmt.Sprintf("    📅 Terhubung: %s\n", startDateTime)
                 listMsg += fmt.Sprintf("    %s Status: %s\n", "🟢", status)
                 listMsg += fmt.Sprintf("    ⏱️  Uptime: %s\n", uptimeStr)
+                listMsg += fmt.Sprintf("    ⏳ Sisa: %s\n", FormatRemaining(session.ExpiresAt))
+                listMsg += fmt.Sprintf("    ⚙️ Setting: %s\n", describeSessionSettings(jm, session.PhoneNumber))
                 listMsg += "\n"
         }
 
         listMsg += "═══════════════════════════════════\n\n"
         listMsg += fmt.Sprintf("📊 *Total Jadibot:* %d aktif\n\n", len(sessions))
+        listMsg += "*⚙️ Ubah Setting:*\n"
+        listMsg += "*.jadibotset [nomor] [key] [on/off/reset]*\n\n"
+        listMsg += "*⏸️ Jeda/Lanjutkan:*\n"
+        listMsg += "*.jadibot pause/resume [nomor]*\n\n"
         listMsg += "*❌ Hapus Jadibot:*\n"
         listMsg += "*.deljadibot [nomor]*\n\n"
         listMsg += "Contoh: *.deljadibot 6288229456210*"
@@ -1307,13 +1523,10 @@ Ketik *.listjadibot* untuk melihat daftar.`
                 return
         }
 
-        phoneNumber := strings.TrimSpace(args)
-        phoneNumber = strings.ReplaceAll(phoneNumber, "+", "")
-        phoneNumber = strings.ReplaceAll(phoneNumber, "-", "")
-        phoneNumber = strings.ReplaceAll(phoneNumber, " ", "")
-
-        jm := GetJadibotManager()
-        err := jm.RemoveSession(phoneNumber)
+        phone, err := utils.ParsePhoneNumber(args, core.Runtime().DefaultRegion)
+        if err == nil {
+                err = GetJadibotManager().RemoveSession(phone.Number)
+        }
 
         if err != nil {
                 errorMsg := fmt.Sprintf("❌ *Gagal menghapus jadibot!*\n\nError: %v\n\nKetik *.listjadibot* untuk melihat daftar.", err)
@@ -1330,7 +1543,7 @@ Ketik *.listjadibot* untuk melihat daftar.`
                 return
         }
 
-        successMsg := fmt.Sprintf("✅ *Jadibot %s berhasil dihapus!*\n\nSemua data telah dibersihkan.", phoneNumber)
+        successMsg := fmt.Sprintf("✅ *Jadibot %s (%s) berhasil dihapus!*\n\nSemua data telah dibersihkan.", phone.Number, phone.Country())
         replyMsg := &waProto.Message{
                 ExtendedTextMessage: &waProto.ExtendedTextMessage{
                         Text: proto.String(successMsg),
@@ -1370,9 +1583,10 @@ func FormatStartDateTime(t time.Time) string {
                 "Juli", "Agustus", "September", "Oktober", "November", "Desember",
         }
         
+        t = t.In(core.Runtime().Location())
         dayName := dayNames[t.Weekday()]
         monthName := monthNames[t.Month()]
-        hour := t.Format("15:04") // Format 24-jam HH:MM
+        hour := t.Format("15:04 MST") // Format 24-jam HH:MM
         
-        return fmt.Sprintf("%s, %d %s %d - %s WIB", dayName, t.Day(), monthName, t.Year(), hour)
+        return fmt.Sprintf("%s, %d %s %d - %s", dayName, t.Day(), monthName, t.Year(), hour)
 }
@@ -0,0 +1,166 @@
+package features
+
+import (
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
+)
+
+// checkQuota enforces jadibot.max_sessions and jadibot.max_per_owner for a
+// new registration by createdBy. Bot owners are only bound by the total.
+// Caller must hold jm.mu.
+func (jm *JadibotManager) checkQuota(mainClient *whatsmeow.Client, createdBy types.JID) error {
+	limits := core.Runtime().Jadibot
+
+	total := len(jm.sessions) + len(jm.pending)
+	if limits.MaxSessions > 0 && total >= limits.MaxSessions {
+		return fmt.Errorf("slot jadibot penuh (%d/%d)", total, limits.MaxSessions)
+	}
+
+	if limits.MaxPerOwner == 0 || createdBy.IsEmpty() {
+		return nil
+	}
+	if mainClient != nil {
+		if ids, _ := utils.ContactIDs(mainClient, createdBy); core.IsOwner(ids...) {
+			return nil
+		}
+	}
+
+	owned := 0
+	for _, group := range []map[string]*JadibotSession{jm.sessions, jm.pending} {
+		for _, session := range group {
+			if session.CreatedBy.User == createdBy.User {
+				owned++
+			}
+		}
+	}
+	if owned >= limits.MaxPerOwner {
+		return fmt.Errorf("kamu sudah punya %d jadibot (maksimal %d)", owned, limits.MaxPerOwner)
+	}
+	return nil
+}
+
+// FormatRemaining describes the time left until expiresAt
+func FormatRemaining(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "♾️ Tanpa batas"
+	}
+	left := time.Until(expiresAt)
+	if left <= 0 {
+		return "Habis"
+	}
+	if left < time.Minute {
+		return "< 1m"
+	}
+	return FormatDuration(left)
+}
+
+// ExtendSession adds d to a session's remaining time, counting from now if
+// it has already run out. A d of 0 or less removes the expiry. It returns
+// the new expiry, zero meaning none.
+func (jm *JadibotManager) ExtendSession(phoneNumber string, d time.Duration) (time.Time, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		return time.Time{}, fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+	}
+
+	previous, previousWarned := session.ExpiresAt, session.ExpiryWarned
+	if d <= 0 {
+		session.ExpiresAt = time.Time{}
+	} else {
+		base := time.Now()
+		if session.ExpiresAt.After(base) {
+			base = session.ExpiresAt
+		}
+		session.ExpiresAt = base.Add(d)
+	}
+	session.ExpiryWarned = false
+
+	if err := saveJadibotMetadata(session); err != nil {
+		session.ExpiresAt, session.ExpiryWarned = previous, previousWarned
+		return previous, fmt.Errorf("gagal menyimpan masa aktif: %v", err)
+	}
+	return session.ExpiresAt, nil
+}
+
+// expiryNotice is a message about one session's expiry, sent by the main
+// bot to the jadibot's own number and to its owner chat
+type expiryNotice struct {
+	phoneNumber string
+	ownerChat   types.JID
+	mainClient  *whatsmeow.Client
+	text        string
+}
+
+// checkExpiry warns sessions that are about to expire and removes the ones
+// that have. It runs with the jadibot health check.
+func (jm *JadibotManager) checkExpiry() {
+	warning := core.Runtime().Jadibot.ExpiryWarning
+	now := time.Now()
+
+	var notices []expiryNotice
+	var expired []string
+
+	jm.mu.Lock()
+	for phoneNumber, session := range jm.sessions {
+		if session.ExpiresAt.IsZero() {
+			continue
+		}
+		if !now.Before(session.ExpiresAt) {
+			expired = append(expired, phoneNumber)
+			notices = append(notices, expiryNotice{
+				phoneNumber: phoneNumber,
+				ownerChat:   session.OwnerChat,
+				mainClient:  jm.mainClientFor(session.Account),
+				text: fmt.Sprintf(`⌛ *MASA AKTIF JADIBOT HABIS*
+
+📱 *Nomor:* %s
+
+Jadibot telah dihentikan dan datanya dihapus.
+Hubungi owner untuk mendaftar lagi.`, phoneNumber),
+			})
+			continue
+		}
+		if !session.ExpiryWarned && session.ExpiresAt.Sub(now) <= warning {
+			session.ExpiryWarned = true
+			if err := saveJadibotMetadata(session); err != nil {
+				fmt.Printf("%s⚠️ Gagal menyimpan metadata jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+			}
+			notices = append(notices, expiryNotice{
+				phoneNumber: phoneNumber,
+				ownerChat:   session.OwnerChat,
+				mainClient:  jm.mainClientFor(session.Account),
+				text: fmt.Sprintf(`⚠️ *MASA AKTIF JADIBOT HAMPIR HABIS*
+
+📱 *Nomor:* %s
+⏳ *Sisa:* %s
+📅 *Berakhir:* %s
+
+Setelah itu jadibot otomatis dihapus.
+Perpanjang: *.jadibot extend %s <durasi>*`, phoneNumber, FormatRemaining(session.ExpiresAt), FormatStartDateTime(session.ExpiresAt), phoneNumber),
+			})
+		}
+	}
+	jm.mu.Unlock()
+
+	// Notify before removing so the expired message goes out first
+	for _, notice := range notices {
+		sendExpiryNotice(notice)
+	}
+	for _, phoneNumber := range expired {
+		fmt.Printf("%s⌛ Masa aktif jadibot %s habis - MENGHAPUS%s\n", ColorYellow, phoneNumber, ColorReset)
+		jm.RemoveSession(phoneNumber)
+	}
+}
+
+func sendExpiryNotice(notice expiryNotice) {
+	sendJadibotNotice(notice.mainClient, notice.phoneNumber, notice.ownerChat, notice.text, true)
+}
@@ -0,0 +1,103 @@
+package features
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"whatsapp-bot/core"
+)
+
+// countStory adds one handled story to a session's counters
+func (jm *JadibotManager) countStory(phoneNumber string, read, reacted bool) {
+	if !read && !reacted {
+		return
+	}
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		return
+	}
+	if read {
+		session.StoriesRead++
+	}
+	if reacted {
+		session.StoriesReacted++
+	}
+}
+
+// SessionInfo returns a detailed report of one session for .jadibotinfo
+func (jm *JadibotManager) SessionInfo(phoneNumber string) (string, error) {
+	jm.mu.RLock()
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		jm.mu.RUnlock()
+		return "", fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+	}
+	s := *session
+	jm.mu.RUnlock()
+	managed, isManaged := GetSessionManager().Status(phoneNumber)
+
+	jid := "-"
+	if s.Client != nil && s.Client.Store != nil && s.Client.Store.ID != nil {
+		jid = s.Client.Store.ID.String()
+	}
+
+	status := "🔴 Offline"
+	switch {
+	case s.Paused:
+		status = fmt.Sprintf("⏸️ Dijeda sejak %s", FormatStartDateTime(s.PausedAt))
+	case isManaged:
+		status = managed.State.Label()
+	}
+
+	var text strings.Builder
+	text.WriteString("🤖 *INFO JADIBOT*\n\n")
+	text.WriteString(fmt.Sprintf("📱 *Nomor:* %s\n", s.PhoneNumber))
+	if s.Label != "" {
+		text.WriteString(fmt.Sprintf("🏷️ *Label:* %s\n", s.Label))
+	}
+	text.WriteString(fmt.Sprintf("🔑 *JID:* %s\n", jid))
+	text.WriteString(fmt.Sprintf("📶 *Status:* %s\n", status))
+	if !s.CreatedBy.IsEmpty() {
+		text.WriteString(fmt.Sprintf("👤 *Didaftarkan oleh:* %s\n", s.CreatedBy.User))
+	}
+
+	text.WriteString("\n⏱️ *WAKTU*\n")
+	text.WriteString(fmt.Sprintf("• Dibuat: %s\n", FormatStartDateTime(s.StartTime)))
+	text.WriteString(fmt.Sprintf("• Uptime: %s\n", FormatDuration(time.Since(s.StartTime))))
+	if !s.LastConnected.IsZero() {
+		text.WriteString(fmt.Sprintf("• Terakhir terhubung: %s\n", FormatStartDateTime(s.LastConnected)))
+	}
+	text.WriteString(fmt.Sprintf("• Sisa masa aktif: %s\n", FormatRemaining(s.ExpiresAt)))
+
+	text.WriteString("\n🩺 *KONEKSI*\n")
+	text.WriteString(fmt.Sprintf("• Gagal berturut-turut: %d/%d\n", managed.FailCount, core.Runtime().HealthCheck.MaxFails))
+	if managed.LastFailTime.IsZero() {
+		text.WriteString("• Gagal terakhir: -\n")
+	} else {
+		text.WriteString(fmt.Sprintf("• Gagal terakhir: %s (%s lalu)\n", FormatStartDateTime(managed.LastFailTime), FormatDuration(time.Since(managed.LastFailTime))))
+	}
+	if s.LastError != "" {
+		text.WriteString(fmt.Sprintf("• Error terakhir: %s (%s)\n", s.LastError, FormatStartDateTime(s.LastErrorTime)))
+	}
+	text.WriteString(fmt.Sprintf("• Status koneksi: %s\n", managed.State))
+	if time.Now().Before(managed.BannedUntil) {
+		text.WriteString(fmt.Sprintf("• Diblokir sementara sampai: %s\n", FormatStartDateTime(managed.BannedUntil)))
+	} else if managed.CircuitOpen() {
+		text.WriteString(fmt.Sprintf("• Circuit terbuka sampai: %s\n", FormatStartDateTime(managed.CircuitUntil)))
+	} else if !managed.NextRetry.IsZero() {
+		text.WriteString(fmt.Sprintf("• Reconnect berikutnya: %s\n", FormatStartDateTime(managed.NextRetry)))
+	}
+	text.WriteString(fmt.Sprintf("• Terhubung/terputus: %d/%d kali\n", managed.Metrics.Connects, managed.Metrics.Disconnects))
+	text.WriteString(fmt.Sprintf("• Percobaan reconnect: %d\n", managed.Metrics.ReconnectAttempts))
+
+	text.WriteString("\n📊 *STORY* _(sejak dimuat)_\n")
+	text.WriteString(fmt.Sprintf("• Dibaca: %d\n", s.StoriesRead))
+	text.WriteString(fmt.Sprintf("• Direaksi: %d\n", s.StoriesReacted))
+
+	text.WriteString(fmt.Sprintf("\n⚙️ *Setting:* %s", describeSessionSettings(jm, s.PhoneNumber)))
+	return text.String(), nil
+}
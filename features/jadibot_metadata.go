@@ -0,0 +1,264 @@
+package features
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
+)
+
+// jadibotMetadataVersion is the current metadata.json layout. Bump it and
+// append a migration to jadibotMetadataMigrations when the layout changes.
+const jadibotMetadataVersion = 1
+
+// jadibotMetadataMigrations[i] upgrades a raw metadata document from
+// version i to version i+1
+var jadibotMetadataMigrations = []func(doc map[string]interface{}) error{
+	// v0 -> v1: files from before versioning only hold phoneNumber,
+	// startTime, savedAt and settings, which keep their names and types.
+	// The fields v1 added (label, owner, history, expiry, pause, account)
+	// start empty; an empty account means the default account.
+	func(doc map[string]interface{}) error {
+		if raw, ok := doc["startTime"]; ok {
+			if _, ok := raw.(float64); !ok {
+				return fmt.Errorf("startTime tidak valid: %v", raw)
+			}
+		}
+		return nil
+	},
+}
+
+// jadibotMetadata is the on-disk layout of a session's metadata.json.
+// Times are unix seconds, 0 meaning never.
+type jadibotMetadata struct {
+	Version       int              `json:"version"`
+	PhoneNumber   string           `json:"phoneNumber"`
+	Label         string           `json:"label,omitempty"`
+	OwnerChat     string           `json:"ownerChat,omitempty"`
+	CreatedBy     string           `json:"createdBy,omitempty"`
+	StartTime     int64            `json:"startTime"`
+	SavedAt       int64            `json:"savedAt"`
+	LastConnected int64            `json:"lastConnected,omitempty"`
+	LastError     string           `json:"lastError,omitempty"`
+	LastErrorAt   int64            `json:"lastErrorAt,omitempty"`
+	ExpiresAt     int64            `json:"expiresAt,omitempty"`
+	ExpiryWarned  bool             `json:"expiryWarned,omitempty"`
+	Paused        bool             `json:"paused,omitempty"`
+	PausedAt      int64            `json:"pausedAt,omitempty"`
+	Settings      core.ConfigPatch `json:"settings,omitempty"`
+	Account       string           `json:"account,omitempty"`
+}
+
+// getJadibotMetadataPath - Get metadata.json path untuk store jadibot info
+func getJadibotMetadataPath(phoneNumber string) string {
+	return core.GetPaths().JadibotMetadata(phoneNumber)
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func timeOrZero(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func jidOrEmpty(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+	return jid.String()
+}
+
+// saveJadibotMetadata - Save info session ke metadata.json. Caller harus
+// memegang jm.mu atau session belum dibagikan.
+func saveJadibotMetadata(session *JadibotSession) error {
+	metadata := jadibotMetadata{
+		Version:       jadibotMetadataVersion,
+		PhoneNumber:   session.PhoneNumber,
+		Label:         session.Label,
+		OwnerChat:     jidOrEmpty(session.OwnerChat),
+		CreatedBy:     jidOrEmpty(session.CreatedBy),
+		StartTime:     session.StartTime.Unix(),
+		SavedAt:       time.Now().Unix(),
+		LastConnected: unixOrZero(session.LastConnected),
+		LastError:     session.LastError,
+		LastErrorAt:   unixOrZero(session.LastErrorTime),
+		ExpiresAt:     unixOrZero(session.ExpiresAt),
+		ExpiryWarned:  session.ExpiryWarned,
+		Paused:        session.Paused,
+		PausedAt:      unixOrZero(session.PausedAt),
+		Settings:      session.Settings,
+		Account:       session.Account,
+	}
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFileAtomic(getJadibotMetadataPath(session.PhoneNumber), data, 0o644)
+}
+
+// migrateJadibotMetadata upgrades doc in place to jadibotMetadataVersion and
+// reports the version it started at. A newer file is left as it is: its
+// known fields still load.
+func migrateJadibotMetadata(doc map[string]interface{}) (int, error) {
+	version := 0
+	if raw, ok := doc["version"]; ok {
+		v, ok := raw.(float64)
+		if !ok || v < 0 || v != float64(int(v)) {
+			return 0, fmt.Errorf("field version tidak valid: %v", raw)
+		}
+		version = int(v)
+	}
+	if version > jadibotMetadataVersion {
+		return version, nil
+	}
+
+	for v := version; v < jadibotMetadataVersion; v++ {
+		if err := jadibotMetadataMigrations[v](doc); err != nil {
+			return version, fmt.Errorf("migrasi v%d -> v%d gagal: %v", v, v+1, err)
+		}
+	}
+	doc["version"] = jadibotMetadataVersion
+	return version, nil
+}
+
+// loadJadibotMetadata - Load metadata.json. File yang hilang/rusak dianggap
+// session baru; field yang rusak diabaikan satu per satu. File lama
+// dimigrasi di memori dan ditulis ulang pada penyimpanan berikutnya.
+func loadJadibotMetadata(phoneNumber string) jadibotMetadata {
+	fresh := jadibotMetadata{Version: jadibotMetadataVersion, PhoneNumber: phoneNumber, StartTime: time.Now().Unix()}
+
+	data, err := os.ReadFile(getJadibotMetadataPath(phoneNumber))
+	if err != nil {
+		return fresh
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		fmt.Printf("%s⚠️ metadata.json jadibot %s rusak: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+		return fresh
+	}
+	fromVersion, err := migrateJadibotMetadata(doc)
+	if err != nil {
+		fmt.Printf("%s⚠️ metadata.json jadibot %s tidak bisa dimuat: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+		return fresh
+	}
+	if fromVersion > jadibotMetadataVersion {
+		fmt.Printf("%s⚠️ metadata.json jadibot %s versi %d lebih baru dari yang didukung (%d), field baru diabaikan%s\n", ColorYellow, phoneNumber, fromVersion, jadibotMetadataVersion, ColorReset)
+	}
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		fmt.Printf("%s⚠️ metadata.json jadibot %s tidak bisa dimuat: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+		return fresh
+	}
+
+	metadata := fresh
+	if err := json.Unmarshal(migrated, &metadata); err != nil {
+		fmt.Printf("%s⚠️ metadata.json jadibot %s rusak: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+		return fresh
+	}
+	metadata.PhoneNumber = phoneNumber
+	if metadata.StartTime == 0 {
+		metadata.StartTime = fresh.StartTime
+	}
+	if err := metadata.Settings.Normalize(); err != nil {
+		fmt.Printf("%s⚠️ Settings jadibot %s diabaikan: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+		metadata.Settings = nil
+	}
+	return metadata
+}
+
+// applyTo restores the persisted fields of a session
+func (m jadibotMetadata) applyTo(session *JadibotSession) {
+	session.Label = m.Label
+	session.StartTime = time.Unix(m.StartTime, 0)
+	session.LastConnected = timeOrZero(m.LastConnected)
+	session.LastError = m.LastError
+	session.LastErrorTime = timeOrZero(m.LastErrorAt)
+	session.ExpiresAt = timeOrZero(m.ExpiresAt)
+	session.ExpiryWarned = m.ExpiryWarned
+	session.Paused = m.Paused
+	session.PausedAt = timeOrZero(m.PausedAt)
+	session.Settings = m.Settings
+	// Sessions from before multi-account mode belong to the default account
+	session.Account = m.Account
+	if session.Account == "" {
+		session.Account = core.DefaultAccount()
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		dest  *types.JID
+	}{
+		{"ownerChat", m.OwnerChat, &session.OwnerChat},
+		{"createdBy", m.CreatedBy, &session.CreatedBy},
+	} {
+		if field.value == "" {
+			continue
+		}
+		jid, err := types.ParseJID(field.value)
+		if err != nil {
+			fmt.Printf("%s⚠️ %s jadibot %s diabaikan: %v%s\n", ColorYellow, field.name, session.PhoneNumber, err, ColorReset)
+			continue
+		}
+		*field.dest = jid
+	}
+}
+
+// recordConnected stores when a session last came online
+func (jm *JadibotManager) recordConnected(phoneNumber string) {
+	jm.updateMetadata(phoneNumber, func(session *JadibotSession) {
+		session.LastConnected = time.Now()
+	})
+}
+
+// recordError stores the last connection problem of a session
+func (jm *JadibotManager) recordError(phoneNumber, reason string) {
+	jm.updateMetadata(phoneNumber, func(session *JadibotSession) {
+		session.LastError = reason
+		session.LastErrorTime = time.Now()
+	})
+}
+
+func (jm *JadibotManager) updateMetadata(phoneNumber string, update func(session *JadibotSession)) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		return
+	}
+	update(session)
+	if err := saveJadibotMetadata(session); err != nil {
+		fmt.Printf("%s⚠️ Gagal menyimpan metadata jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+	}
+}
+
+// SetSessionLabel names a session, e.g. after its user; "" removes the label
+func (jm *JadibotManager) SetSessionLabel(phoneNumber, label string) error {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		return fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+	}
+	previous := session.Label
+	session.Label = label
+	if err := saveJadibotMetadata(session); err != nil {
+		session.Label = previous
+		return fmt.Errorf("gagal menyimpan label jadibot: %v", err)
+	}
+	return nil
+}
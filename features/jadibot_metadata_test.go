@@ -0,0 +1,56 @@
+package features
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMigrateJadibotMetadata(t *testing.T) {
+	tests := []struct {
+		name        string
+		doc         map[string]interface{}
+		wantVersion int
+		wantDoc     map[string]interface{}
+		wantErr     bool
+	}{
+		{
+			name:        "file from before versioning",
+			doc:         map[string]interface{}{"phoneNumber": "6281234567890", "startTime": float64(1700000000), "savedAt": float64(1700000100)},
+			wantVersion: 0,
+			wantDoc:     map[string]interface{}{"version": jadibotMetadataVersion, "phoneNumber": "6281234567890", "startTime": float64(1700000000), "savedAt": float64(1700000100)},
+		},
+		{
+			name:        "current version is left as it is",
+			doc:         map[string]interface{}{"version": float64(jadibotMetadataVersion), "label": "kantor"},
+			wantVersion: jadibotMetadataVersion,
+			wantDoc:     map[string]interface{}{"version": jadibotMetadataVersion, "label": "kantor"},
+		},
+		{
+			name:        "newer version is not touched",
+			doc:         map[string]interface{}{"version": float64(jadibotMetadataVersion + 1)},
+			wantVersion: jadibotMetadataVersion + 1,
+			wantDoc:     map[string]interface{}{"version": float64(jadibotMetadataVersion + 1)},
+		},
+		{name: "version is not a number", doc: map[string]interface{}{"version": "1"}, wantErr: true},
+		{name: "negative version", doc: map[string]interface{}{"version": float64(-1)}, wantErr: true},
+		{name: "fractional version", doc: map[string]interface{}{"version": 0.5}, wantErr: true},
+		{name: "v0 startTime is not a number", doc: map[string]interface{}{"startTime": "kemarin"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		version, err := migrateJadibotMetadata(tt.doc)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: migrateJadibotMetadata = %d, want error", tt.name, version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: migrateJadibotMetadata error: %v", tt.name, err)
+			continue
+		}
+		if version != tt.wantVersion || !reflect.DeepEqual(tt.doc, tt.wantDoc) {
+			t.Errorf("%s: migrateJadibotMetadata = %d, %v; want %d, %v", tt.name, version, tt.doc, tt.wantVersion, tt.wantDoc)
+		}
+	}
+}
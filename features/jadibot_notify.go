@@ -0,0 +1,225 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"whatsapp-bot/core"
+)
+
+// lifecycleEvent is something that happened to a session on its own, which
+// its owner is told about
+type lifecycleEvent int
+
+const (
+	// eventDisconnected and eventReconnected can repeat while a connection
+	// flaps, so they are rate limited by jadibot.notify_interval
+	eventDisconnected lifecycleEvent = iota
+	eventReconnected
+	// eventRemoved ends the session. It is sent once per reason: the same
+	// removal repeating (e.g. files that can't be deleted) is rate limited
+	// like the events above.
+	eventRemoved
+)
+
+// lifecycleNotifier remembers what was sent to each session's owner
+type lifecycleNotifier struct {
+	mu   sync.Mutex
+	last map[string]map[lifecycleEvent]time.Time
+	// awaitingReconnect marks sessions whose owner was told about a
+	// disconnect and has not heard about the reconnect yet
+	awaitingReconnect map[string]bool
+	// removed is the last removal reason sent for each session
+	removed map[string]removedNotice
+}
+
+type removedNotice struct {
+	reason string
+	at     time.Time
+}
+
+// allow reports whether event may be sent for phoneNumber now, and records
+// it if so
+func (n *lifecycleNotifier) allow(phoneNumber string, event lifecycleEvent) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.init()
+
+	switch event {
+	case eventReconnected:
+		// Only worth saying if the owner heard about the disconnect
+		if !n.awaitingReconnect[phoneNumber] {
+			return false
+		}
+	}
+
+	interval := core.Runtime().Jadibot.NotifyInterval
+	if sent, ok := n.last[phoneNumber][event]; ok && interval > 0 && time.Since(sent) < interval {
+		return false
+	}
+	if n.last[phoneNumber] == nil {
+		n.last[phoneNumber] = make(map[lifecycleEvent]time.Time)
+	}
+	n.last[phoneNumber][event] = time.Now()
+	n.awaitingReconnect[phoneNumber] = event == eventDisconnected
+	return true
+}
+
+// allowRemoved reports whether a removal notice with reason may be sent for
+// phoneNumber now, and records it if so. It forgets the session's other
+// events, since a new session with the number starts fresh.
+func (n *lifecycleNotifier) allowRemoved(phoneNumber, reason string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.init()
+
+	delete(n.last, phoneNumber)
+	delete(n.awaitingReconnect, phoneNumber)
+
+	interval := core.Runtime().Jadibot.NotifyInterval
+	if sent, ok := n.removed[phoneNumber]; ok && sent.reason == reason && interval > 0 && time.Since(sent.at) < interval {
+		return false
+	}
+	n.removed[phoneNumber] = removedNotice{reason: reason, at: time.Now()}
+	return true
+}
+
+func (n *lifecycleNotifier) init() {
+	if n.last == nil {
+		n.last = make(map[string]map[lifecycleEvent]time.Time)
+		n.awaitingReconnect = make(map[string]bool)
+		n.removed = make(map[string]removedNotice)
+	}
+}
+
+func lifecycleText(phoneNumber string, event lifecycleEvent, reason string) string {
+	switch event {
+	case eventDisconnected:
+		return fmt.Sprintf(`⚠️ *JADIBOT TERPUTUS*
+
+📱 *Nomor:* %s
+❓ *Penyebab:* %s
+
+Bot sedang mencoba menghubungkan kembali secara otomatis.`, phoneNumber, reason)
+	case eventReconnected:
+		return fmt.Sprintf(`✅ *JADIBOT TERHUBUNG KEMBALI*
+
+📱 *Nomor:* %s
+🔄 *Keterangan:* %s`, phoneNumber, reason)
+	default:
+		return fmt.Sprintf(`🗑️ *JADIBOT DIHAPUS*
+
+📱 *Nomor:* %s
+❓ *Penyebab:* %s
+
+Session dan datanya sudah dihapus.
+Ketik *.jadibot %s* untuk mendaftar lagi.`, phoneNumber, reason, phoneNumber)
+	}
+}
+
+// ownerOf returns the chat that registered phoneNumber and the client of
+// the main account it belongs to, from the live session or, for sessions
+// that failed to load, its metadata.json
+func (jm *JadibotManager) ownerOf(phoneNumber string) (types.JID, *whatsmeow.Client) {
+	jm.mu.RLock()
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		session, exists = jm.pending[phoneNumber]
+	}
+	if exists {
+		defer jm.mu.RUnlock()
+		return session.OwnerChat, jm.mainClientFor(session.Account)
+	}
+	jm.mu.RUnlock()
+
+	metadata := loadJadibotMetadata(phoneNumber)
+	owner, _ := types.ParseJID(metadata.OwnerChat)
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	return owner, jm.mainClientFor(metadata.Account)
+}
+
+// notifyLifecycle tells a session's owner (and, with jadibot.notify_self,
+// the jadibot number itself) about event. Callers must not hold jm.mu.
+func (jm *JadibotManager) notifyLifecycle(phoneNumber string, event lifecycleEvent, reason string) {
+	if !jm.notifier.allow(phoneNumber, event) {
+		return
+	}
+	ownerChat, mainClient := jm.ownerOf(phoneNumber)
+
+	go sendJadibotNotice(mainClient, phoneNumber, ownerChat, lifecycleText(phoneNumber, event, reason), core.Runtime().Jadibot.NotifySelf)
+}
+
+// notifyOwner sends a SessionManager notice to a session's owner. An
+// unstable connection counts as a disconnect, so the owner doesn't also get
+// the plain disconnect message.
+func (jm *JadibotManager) notifyOwner(phoneNumber string, notice Notice) {
+	if notice.Kind == NoticeUnstable && !jm.notifier.allow(phoneNumber, eventDisconnected) {
+		return
+	}
+	ownerChat, mainClient := jm.ownerOf(phoneNumber)
+
+	go sendJadibotNotice(mainClient, phoneNumber, ownerChat, notice.Text, core.Runtime().Jadibot.NotifySelf)
+}
+
+// removeWithNotice removes a session that failed on its own and tells the
+// owner why. Only the first of several concurrent removals notifies.
+func (jm *JadibotManager) removeWithNotice(phoneNumber, reason string) {
+	ownerChat, mainClient := jm.ownerOf(phoneNumber)
+	if err := jm.RemoveSession(phoneNumber); err != nil {
+		return
+	}
+	jm.sendRemovedNotice(mainClient, phoneNumber, ownerChat, reason)
+}
+
+// deleteFilesWithNotice deletes the files of a session that could not be
+// loaded and tells the owner recorded in its metadata why
+func (jm *JadibotManager) deleteFilesWithNotice(phoneNumber, reason string) {
+	ownerChat, mainClient := jm.ownerOf(phoneNumber)
+	jm.deleteJadibotFiles(phoneNumber)
+	jm.sendRemovedNotice(mainClient, phoneNumber, ownerChat, reason)
+}
+
+func (jm *JadibotManager) sendRemovedNotice(mainClient *whatsmeow.Client, phoneNumber string, ownerChat types.JID, reason string) {
+	if !jm.notifier.allowRemoved(phoneNumber, reason) {
+		return
+	}
+
+	go sendJadibotNotice(mainClient, phoneNumber, ownerChat, lifecycleText(phoneNumber, eventRemoved, reason), core.Runtime().Jadibot.NotifySelf)
+}
+
+// sendJadibotNotice sends text from the main bot to ownerChat and, with
+// toSelf, to the jadibot's own number
+func sendJadibotNotice(mainClient *whatsmeow.Client, phoneNumber string, ownerChat types.JID, text string, toSelf bool) {
+	if mainClient == nil || !mainClient.IsConnected() {
+		fmt.Printf("%s⚠️ Notif jadibot %s tidak terkirim: bot utama tidak terhubung%s\n", ColorYellow, phoneNumber, ColorReset)
+		return
+	}
+
+	var targets []types.JID
+	self := types.NewJID(phoneNumber, types.DefaultUserServer)
+	if toSelf {
+		targets = append(targets, self)
+	}
+	if !ownerChat.IsEmpty() && (!toSelf || ownerChat.ToNonAD() != self) {
+		targets = append(targets, ownerChat)
+	}
+	for _, target := range targets {
+		msg := &waProto.Message{
+			ExtendedTextMessage: &waProto.ExtendedTextMessage{
+				Text: proto.String(text),
+			},
+		}
+		if _, err := mainClient.SendMessage(context.Background(), target, msg); err != nil {
+			fmt.Printf("%s⚠️ Notif jadibot %s ke %s gagal: %v%s\n", ColorYellow, phoneNumber, target, err, ColorReset)
+		}
+	}
+}
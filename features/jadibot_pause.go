@@ -0,0 +1,86 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// PauseSession disconnects a session but keeps its database, so it can be
+// resumed later. The paused state survives restarts.
+func (jm *JadibotManager) PauseSession(phoneNumber string) error {
+	jm.mu.Lock()
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		jm.mu.Unlock()
+		return fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+	}
+	if session.Paused {
+		jm.mu.Unlock()
+		return fmt.Errorf("jadibot %s sudah dijeda", phoneNumber)
+	}
+
+	session.Paused = true
+	session.PausedAt = time.Now()
+	if err := saveJadibotMetadata(session); err != nil {
+		session.Paused = false
+		session.PausedAt = time.Time{}
+		jm.mu.Unlock()
+		return fmt.Errorf("gagal menyimpan status jeda: %v", err)
+	}
+	client := session.Client
+	jm.mu.Unlock()
+
+	GetSessionManager().SetPaused(phoneNumber, true)
+	if client != nil {
+		client.Disconnect()
+	}
+	fmt.Printf("%s⏸️ Jadibot %s dijeda%s\n", ColorYellow, phoneNumber, ColorReset)
+	return nil
+}
+
+// ResumeSession reconnects a paused session. It stays paused if the
+// connection fails.
+func (jm *JadibotManager) ResumeSession(phoneNumber string) error {
+	jm.mu.RLock()
+	session, exists := jm.sessions[phoneNumber]
+	if !exists {
+		jm.mu.RUnlock()
+		return fmt.Errorf("jadibot %s tidak ditemukan", phoneNumber)
+	}
+	paused, client := session.Paused, session.Client
+	jm.mu.RUnlock()
+
+	if !paused {
+		return fmt.Errorf("jadibot %s tidak sedang dijeda", phoneNumber)
+	}
+	if client == nil || client.Store.ID == nil {
+		return fmt.Errorf("session jadibot %s tidak valid, hapus lalu daftar ulang", phoneNumber)
+	}
+
+	sm := GetSessionManager()
+	if err := sm.SetPaused(phoneNumber, false); err != nil {
+		return err
+	}
+	if !client.IsConnected() {
+		if err := client.Connect(); err != nil {
+			sm.SetPaused(phoneNumber, true)
+			jm.recordError(phoneNumber, fmt.Sprintf("gagal resume: %v", err))
+			return fmt.Errorf("gagal menghubungkan jadibot %s: %v", phoneNumber, err)
+		}
+	}
+
+	jm.mu.Lock()
+	session.Paused = false
+	session.PausedAt = time.Time{}
+	if err := saveJadibotMetadata(session); err != nil {
+		fmt.Printf("%s⚠️ Gagal menyimpan metadata jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+	}
+	jm.mu.Unlock()
+
+	client.SendPresence(context.Background(), types.PresenceAvailable)
+	fmt.Printf("%s▶️ Jadibot %s dilanjutkan%s\n", ColorGreen, phoneNumber, ColorReset)
+	return nil
+}
@@ -0,0 +1,145 @@
+package features
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+	"rsc.io/qr"
+)
+
+// qrScale is the size in pixels of one QR module in the PNG sent to chat
+const qrScale = 8
+
+// RenderQRPNG renders a pairing QR code as a PNG image
+func RenderQRPNG(code string) ([]byte, error) {
+	encoded, err := qr.Encode(code, qr.L)
+	if err != nil {
+		return nil, err
+	}
+	encoded.Scale = qrScale
+	return encoded.PNG(), nil
+}
+
+// sendImage uploads a PNG image and sends it to chat, returning the ID of
+// the sent message
+func sendImage(client *whatsmeow.Client, chat types.JID, data []byte, caption string) (types.MessageID, error) {
+	ctx := context.Background()
+	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaImage)
+	if err != nil {
+		return "", err
+	}
+
+	msg := &waProto.Message{
+		ImageMessage: &waProto.ImageMessage{
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			Mimetype:      proto.String("image/png"),
+			FileLength:    proto.Uint64(uint64(len(data))),
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			Caption:       proto.String(caption),
+		},
+	}
+	resp, err := client.SendMessage(ctx, chat, msg)
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// sendPairingQR sends every QR code of a pending jadibot to its owner chat
+// as an image, deleting the previous one when a new code arrives. Expiry is
+// left to waitForPairing, except when WhatsApp stops issuing codes earlier.
+func (jm *JadibotManager) sendPairingQR(ctx context.Context, phoneNumber string, qrChan <-chan whatsmeow.QRChannelItem) {
+	var previous types.MessageID
+	count := 0
+	// revokePrevious deletes the last QR sent, which can't be used anymore
+	revokePrevious := func(client *whatsmeow.Client, chat types.JID) {
+		if previous != "" {
+			client.SendMessage(context.Background(), chat, client.BuildRevoke(chat, types.EmptyJID, previous))
+			previous = ""
+		}
+	}
+
+	for {
+		var evt whatsmeow.QRChannelItem
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-qrChan:
+			if !ok {
+				return
+			}
+			evt = item
+		}
+
+		jm.mu.RLock()
+		session, pending := jm.pending[phoneNumber]
+		var mainClient *whatsmeow.Client
+		var ownerChat types.JID
+		if pending {
+			ownerChat = session.OwnerChat
+			mainClient = jm.mainClientFor(session.Account)
+		}
+		jm.mu.RUnlock()
+
+		if !pending || mainClient == nil || ownerChat.IsEmpty() {
+			return
+		}
+
+		switch evt.Event {
+		case whatsmeow.QRChannelEventCode:
+			count++
+			png, err := RenderQRPNG(evt.Code)
+			if err != nil {
+				fmt.Printf("%s⚠️ Gagal membuat QR jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+				continue
+			}
+
+			caption := fmt.Sprintf(`📷 *QR JADIBOT #%d*
+
+📱 *Nomor:* %s
+⏱️ *Berlaku:* %d detik
+
+1️⃣ Buka WhatsApp di HP nomor tersebut
+2️⃣ Tap ⋮ → "Perangkat Tertaut" → "Tautkan Perangkat"
+3️⃣ Scan QR ini
+
+_QR baru dikirim otomatis saat yang ini kedaluwarsa._`, count, phoneNumber, int(evt.Timeout.Seconds()))
+
+			id, err := sendImage(mainClient, ownerChat, png, caption)
+			if err != nil {
+				fmt.Printf("%s⚠️ Gagal mengirim QR jadibot %s: %v%s\n", ColorYellow, phoneNumber, err, ColorReset)
+				continue
+			}
+			revokePrevious(mainClient, ownerChat)
+			previous = id
+			fmt.Printf("%s📷 QR jadibot %s #%d dikirim%s\n", ColorCyan, phoneNumber, count, ColorReset)
+
+		case whatsmeow.QRChannelSuccess.Event:
+			revokePrevious(mainClient, ownerChat)
+			return
+
+		case whatsmeow.QRChannelTimeout.Event:
+			// No more codes: expire now instead of waiting for the timer
+			revokePrevious(mainClient, ownerChat)
+			jm.expirePairing(phoneNumber)
+			return
+
+		default:
+			reason := evt.Event
+			if evt.Error != nil {
+				reason = evt.Error.Error()
+			}
+			fmt.Printf("%s⚠️ QR pairing jadibot %s gagal: %s%s\n", ColorYellow, phoneNumber, reason, ColorReset)
+			revokePrevious(mainClient, ownerChat)
+			jm.expirePairing(phoneNumber)
+			return
+		}
+	}
+}
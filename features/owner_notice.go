@@ -0,0 +1,80 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+
+	"whatsapp-bot/core"
+)
+
+// ownerNotices holds, per main account, notices for the bot owners that
+// were raised while the account was offline (it can't message anyone then);
+// they are sent once it is connected again
+var ownerNotices struct {
+	mu     sync.Mutex
+	queues map[string][]string
+}
+
+func init() {
+	SubscribeState(func(change StateChange) {
+		if change.Primary && change.To == StateConnected {
+			go flushOwnerNotices(change.ID)
+		}
+	})
+}
+
+// NotifyOwners sends notice to every bot owner from the main account, or
+// queues it until the account is connected. Without owners in roles.json
+// it goes to the account's own chat. It is a main account's OnNotice.
+func NotifyOwners(account string, notice Notice) {
+	ownerNotices.mu.Lock()
+	if ownerNotices.queues == nil {
+		ownerNotices.queues = make(map[string][]string)
+	}
+	ownerNotices.queues[account] = append(ownerNotices.queues[account], notice.Text)
+	ownerNotices.mu.Unlock()
+	flushOwnerNotices(account)
+}
+
+func flushOwnerNotices(account string) {
+	primary := GetSessionManager().Get(account)
+	if primary == nil || primary.Client == nil || !primary.Client.IsConnected() {
+		return
+	}
+
+	ownerNotices.mu.Lock()
+	queue := ownerNotices.queues[account]
+	delete(ownerNotices.queues, account)
+	ownerNotices.mu.Unlock()
+
+	owners := core.GetRoleConfig().Owners
+	if len(owners) == 0 && primary.Client.Store.ID != nil {
+		owners = []string{primary.Client.Store.ID.ToNonAD().String()}
+	}
+	for _, text := range queue {
+		for _, owner := range owners {
+			jid := types.NewJID(owner, types.DefaultUserServer)
+			if strings.Contains(owner, "@") {
+				parsed, err := types.ParseJID(owner)
+				if err != nil {
+					continue
+				}
+				jid = parsed
+			}
+			msg := &waProto.Message{
+				ExtendedTextMessage: &waProto.ExtendedTextMessage{
+					Text: proto.String(text),
+				},
+			}
+			if _, err := primary.Client.SendMessage(context.Background(), jid, msg); err != nil {
+				fmt.Printf("%s⚠️ %sNotif ke owner %s gagal: %v%s\n", ColorYellow, core.AccountTag(account), owner, err, ColorReset)
+			}
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsapp-bot/core"
+)
+
+// NoticeKind says what a Notice is about
+type NoticeKind int
+
+const (
+	// NoticeLoggedOut: the device was unlinked and the session deleted
+	NoticeLoggedOut NoticeKind = iota
+	// NoticeBanned: WhatsApp banned the account for a while
+	NoticeBanned
+	// NoticeOutdated: WhatsApp rejected the client version
+	NoticeOutdated
+	// NoticeUnstable: keepalives failed and the connection was restarted
+	NoticeUnstable
+	// NoticeConnectFailure: WhatsApp refused the connection for another reason
+	NoticeConnectFailure
+)
+
+// Notice is something about a session its owner has to hear about. Each
+// kind is sent once per outage; the session connecting again rearms it.
+type Notice struct {
+	Kind NoticeKind
+	Text string
+}
+
+// notify sends notice to the session's owner unless it was already sent
+// during this outage
+func (sm *SessionManager) notify(session *ManagedSession, notice Notice) {
+	sm.mu.Lock()
+	if session.noticed == nil {
+		session.noticed = make(map[NoticeKind]bool)
+	}
+	sent := session.noticed[notice.Kind]
+	session.noticed[notice.Kind] = true
+	sm.mu.Unlock()
+
+	if sent || session.OnNotice == nil {
+		return
+	}
+	session.OnNotice(notice)
+}
+
+// AutomationPaused reports whether a session's automation (stories,
+// presence, commands) is on hold because the account is banned for a while
+func (sm *SessionManager) AutomationPaused(id string) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	session, exists := sm.sessions[id]
+	return exists && time.Now().Before(session.bannedUntil)
+}
+
+// handleFailureEvent handles the events that say why WhatsApp dropped or
+// refused a session. It reports whether evt was one of them.
+func (sm *SessionManager) handleFailureEvent(session *ManagedSession, evt interface{}) bool {
+	switch v := evt.(type) {
+	case *events.LoggedOut:
+		sm.handleLoggedOut(session, v)
+	case *events.TemporaryBan:
+		sm.handleTemporaryBan(session, v)
+	case *events.ClientOutdated:
+		sm.handleClientOutdated(session)
+	case *events.KeepAliveTimeout:
+		sm.handleKeepAliveTimeout(session, v)
+	case *events.KeepAliveRestored:
+		fmt.Printf("%s✅ %s: keepalive pulih%s\n", ColorGreen, session.label(), ColorReset)
+	case *events.ConnectFailure:
+		sm.handleConnectFailure(session, v)
+	default:
+		return false
+	}
+	return true
+}
+
+// suspend stops the running reconnect loop and holds back reconnects until
+// until, moving the session to backoff. sm.mu must be held.
+func (sm *SessionManager) suspend(session *ManagedSession, until time.Time, reason string) *StateChange {
+	if session.reconnectCancel != nil {
+		session.reconnectCancel()
+		session.reconnectCancel = nil
+	}
+	session.circuitUntil = until
+	session.nextRetry = until
+	session.lastError = reason
+	session.lastFailTime = time.Now()
+	return sm.transition(session, StateBackoff, reason)
+}
+
+// handleLoggedOut: whatsmeow already deleted the device, so reconnecting
+// can never work. The owner cleans the session up and re-pairs (OnLoggedOut)
+// or, without one, gives up on it.
+func (sm *SessionManager) handleLoggedOut(session *ManagedSession, evt *events.LoggedOut) {
+	reason := "perangkat tertaut dikeluarkan dari WhatsApp di HP"
+	if evt.OnConnect {
+		reason = fmt.Sprintf("WhatsApp menolak koneksi: %s", evt.Reason)
+	}
+
+	sm.mu.Lock()
+	if session.reconnectCancel != nil {
+		session.reconnectCancel()
+		session.reconnectCancel = nil
+	}
+	change := sm.transition(session, StateLoggedOut, reason)
+	sm.mu.Unlock()
+	if change != nil {
+		publishState(*change)
+	}
+
+	if session.OnLoggedOut == nil {
+		sm.giveUp(session, reason)
+		return
+	}
+	sm.notify(session, Notice{Kind: NoticeLoggedOut, Text: fmt.Sprintf(`🚫 *BOT LOGOUT*
+
+📱 *Nomor:* %s
+❓ *Penyebab:* %s
+
+Session lama sudah dihapus dan pairing ulang dimulai. Cek terminal untuk kode pairing/QR baru.`, session.ID, reason)})
+	go session.OnLoggedOut(reason)
+}
+
+// handleTemporaryBan pauses all automation of the session and holds back
+// reconnects until the ban expires
+func (sm *SessionManager) handleTemporaryBan(session *ManagedSession, evt *events.TemporaryBan) {
+	expire := evt.Expire
+	if expire <= 0 {
+		expire = core.Runtime().Reconnect.CircuitCooldown
+	}
+	until := time.Now().Add(expire)
+	reason := fmt.Sprintf("diblokir sementara oleh WhatsApp (%s)", evt.Code)
+
+	sm.mu.Lock()
+	session.bannedUntil = until
+	change := sm.suspend(session, until, reason)
+	sm.mu.Unlock()
+	if change != nil {
+		publishState(*change)
+	}
+
+	fmt.Printf("%s⛔ %s: %s sampai %s%s\n", ColorYellow, session.label(), reason, FormatStartDateTime(until), ColorReset)
+	if session.OnError != nil {
+		session.OnError(reason)
+	}
+	sm.notify(session, Notice{Kind: NoticeBanned, Text: fmt.Sprintf(`⛔ *AKUN DIBLOKIR SEMENTARA*
+
+📱 *Nomor:* %s
+❓ *Alasan:* %s
+⏰ *Berakhir:* %s (%s lagi)
+
+Semua otomatisasi (story, presence, command) dihentikan dan bot tidak mencoba terhubung sampai blokir berakhir.`, session.ID, evt.Code, FormatStartDateTime(until), FormatDuration(expire))})
+}
+
+// handleClientOutdated switches every client to the current WhatsApp Web
+// version and reconnects. If the version can't be updated, reconnects wait
+// for reconnect.circuit_cooldown; the bot itself needs an update then.
+func (sm *SessionManager) handleClientOutdated(session *ManagedSession) {
+	ctx, cancel := context.WithTimeout(session.ctx, 30*time.Second)
+	version, updated, err := refreshWAVersion(ctx)
+	cancel()
+
+	reason := "versi WhatsApp Web bot ditolak (kedaluwarsa)"
+	sm.mu.Lock()
+	var change *StateChange
+	if updated {
+		if session.reconnectCancel != nil {
+			session.reconnectCancel()
+			session.reconnectCancel = nil
+		}
+		session.lastError = reason
+		change = sm.transition(session, StateBackoff, reason)
+	} else {
+		change = sm.suspend(session, time.Now().Add(core.Runtime().Reconnect.CircuitCooldown), reason)
+	}
+	until := session.circuitUntil
+	sm.mu.Unlock()
+	if change != nil {
+		publishState(*change)
+	}
+
+	if updated {
+		fmt.Printf("%s🔄 %s: versi WhatsApp Web diperbarui ke %s, mencoba reconnect...%s\n", ColorYellow, session.label(), version, ColorReset)
+		sm.notify(session, Notice{Kind: NoticeOutdated, Text: fmt.Sprintf(`⚠️ *VERSI WHATSAPP KEDALUWARSA*
+
+📱 *Nomor:* %s
+
+WhatsApp menolak versi bot. Versi otomatis diperbarui ke %s dan bot mencoba terhubung kembali. Perbarui bot jika masalah berulang.`, session.ID, version)})
+		go sm.Reconnect(session.ID)
+		return
+	}
+
+	fmt.Printf("%s❌ %s: %s, gagal memperbarui versi: %v%s\n", ColorYellow, session.label(), reason, err, ColorReset)
+	sm.notify(session, Notice{Kind: NoticeOutdated, Text: fmt.Sprintf(`❌ *VERSI WHATSAPP KEDALUWARSA*
+
+📱 *Nomor:* %s
+
+WhatsApp menolak versi bot dan versi terbaru tidak bisa diambil (%v). Bot dicoba lagi %s; perbarui bot (go.mau.fi/whatsmeow) secepatnya.`, session.ID, err, FormatStartDateTime(until))})
+}
+
+// refreshWAVersion fetches the current WhatsApp Web version and makes every
+// client use it. It reports whether the version changed.
+func refreshWAVersion(ctx context.Context) (string, bool, error) {
+	latest, err := whatsmeow.GetLatestVersion(ctx, nil)
+	if err != nil {
+		return "", false, err
+	}
+	if *latest == store.GetWAVersion() {
+		return latest.String(), false, fmt.Errorf("versi %s sudah yang terbaru", latest)
+	}
+	store.SetWAVersion(*latest)
+	return latest.String(), true, nil
+}
+
+// handleKeepAliveTimeout restarts a connection whose keepalives have failed
+// for longer than whatsmeow.KeepAliveMaxFailTime, through the reconnect
+// backoff. Shorter hiccups are only logged.
+func (sm *SessionManager) handleKeepAliveTimeout(session *ManagedSession, evt *events.KeepAliveTimeout) {
+	silent := time.Since(evt.LastSuccess)
+	fmt.Printf("%s⚠️ %s: keepalive gagal %d kali (terakhir berhasil %s lalu)%s\n", ColorYellow, session.label(), evt.ErrorCount, FormatDuration(silent), ColorReset)
+	if silent < whatsmeow.KeepAliveMaxFailTime {
+		return
+	}
+
+	reason := fmt.Sprintf("keepalive gagal selama %s", FormatDuration(silent))
+	sm.mu.Lock()
+	if session.state != StateConnected {
+		sm.mu.Unlock()
+		return
+	}
+	session.lastError = reason
+	session.lastFailTime = time.Now()
+	sm.mu.Unlock()
+
+	// Told before the state change so owners get this reason rather than a
+	// plain disconnect notice
+	sm.notify(session, Notice{Kind: NoticeUnstable, Text: fmt.Sprintf(`⚠️ *KONEKSI TIDAK STABIL*
+
+📱 *Nomor:* %s
+❓ *Penyebab:* %s
+
+Koneksi diputus dan dihubungkan ulang dengan jeda bertahap.`, session.ID, reason)})
+
+	sm.mu.Lock()
+	change := sm.transition(session, StateBackoff, reason)
+	sm.mu.Unlock()
+	if change == nil {
+		return
+	}
+	publishState(*change)
+	session.Client.Disconnect()
+	if session.OnError != nil {
+		session.OnError(reason)
+	}
+	go sm.Reconnect(session.ID)
+}
+
+// handleConnectFailure reconnects with backoff after WhatsApp refused the
+// connection for a reason whatsmeow doesn't handle itself
+func (sm *SessionManager) handleConnectFailure(session *ManagedSession, evt *events.ConnectFailure) {
+	reason := fmt.Sprintf("koneksi ditolak WhatsApp: %s", evt.Reason)
+	if evt.Message != "" {
+		reason += " - " + evt.Message
+	}
+
+	sm.mu.Lock()
+	session.lastError = reason
+	session.lastFailTime = time.Now()
+	change := sm.transition(session, StateBackoff, reason)
+	sm.mu.Unlock()
+	if change != nil {
+		publishState(*change)
+	}
+
+	fmt.Printf("%s⚠️ %s: %s%s\n", ColorYellow, session.label(), reason, ColorReset)
+	if session.OnError != nil {
+		session.OnError(reason)
+	}
+	sm.notify(session, Notice{Kind: NoticeConnectFailure, Text: fmt.Sprintf(`⚠️ *KONEKSI DITOLAK WHATSAPP*
+
+📱 *Nomor:* %s
+❓ *Penyebab:* %s
+
+Bot mencoba terhubung kembali dengan jeda bertahap.`, session.ID, reason)})
+	go sm.Reconnect(session.ID)
+}
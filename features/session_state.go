@@ -0,0 +1,213 @@
+package features
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SessionState is where a managed session is in its connection lifecycle.
+// Every change goes through SessionManager.transition, which rejects
+// changes the table below doesn't allow.
+type SessionState int
+
+const (
+	// StatePending is a session that was added but not started yet
+	StatePending SessionState = iota
+	// StatePairing waits for a pairing code or QR scan
+	StatePairing
+	// StateConnecting has a connection attempt in progress
+	StateConnecting
+	StateConnected
+	// StateBackoff waits before the next reconnect attempt
+	StateBackoff
+	// StatePaused is deliberately offline: not reconnected or health checked
+	StatePaused
+	// StateLoggedOut was rejected or unlinked by WhatsApp
+	StateLoggedOut
+	// StateRemoved is final; the session's context is cancelled
+	StateRemoved
+)
+
+var stateNames = map[SessionState]string{
+	StatePending:    "pending",
+	StatePairing:    "pairing",
+	StateConnecting: "connecting",
+	StateConnected:  "connected",
+	StateBackoff:    "backoff",
+	StatePaused:     "paused",
+	StateLoggedOut:  "logged-out",
+	StateRemoved:    "removed",
+}
+
+func (s SessionState) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("state(%d)", int(s))
+}
+
+var stateLabels = map[SessionState]string{
+	StatePending:    "⚪ Menunggu",
+	StatePairing:    "🔗 Pairing",
+	StateConnecting: "🔄 Menghubungkan",
+	StateConnected:  "🟢 Online",
+	StateBackoff:    "⏳ Menunggu reconnect",
+	StatePaused:     "⏸️ Dijeda",
+	StateLoggedOut:  "🚫 Logout",
+	StateRemoved:    "🗑️ Dihapus",
+}
+
+// Label describes the state for chat replies
+func (s SessionState) Label() string {
+	if label, ok := stateLabels[s]; ok {
+		return label
+	}
+	return s.String()
+}
+
+// stateTransitions lists the states each state may move to. Staying in the
+// same state is always allowed and does nothing.
+var stateTransitions = map[SessionState][]SessionState{
+	StatePending:    {StatePairing, StateConnecting, StateConnected, StatePaused, StateRemoved},
+	StatePairing:    {StateConnecting, StateConnected, StateLoggedOut, StateRemoved},
+	StateConnecting: {StateConnected, StateBackoff, StateLoggedOut, StatePaused, StateRemoved},
+	StateConnected:  {StateBackoff, StateLoggedOut, StatePaused, StateRemoved},
+	StateBackoff:    {StateConnecting, StateConnected, StateLoggedOut, StatePaused, StateRemoved},
+	StatePaused:     {StateConnecting, StateRemoved},
+	StateLoggedOut:  {StateBackoff, StateConnecting, StateRemoved},
+	StateRemoved:    nil,
+}
+
+// CanTransition reports whether a session may move from one state to another
+func CanTransition(from, to SessionState) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range stateTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StateChange is published after a session changed state
+type StateChange struct {
+	ID      string
+	Primary bool
+	Account string
+	From    SessionState
+	To      SessionState
+	Reason  string
+	At      time.Time
+}
+
+// StateListener is called after a session changes state
+type StateListener func(change StateChange)
+
+type stateSubscription struct {
+	id       int
+	listener StateListener
+}
+
+var (
+	stateSubs   []stateSubscription
+	stateSubsID int
+	stateSubsMu sync.RWMutex
+)
+
+// SubscribeState registers listener for the state changes of every managed
+// session. Listeners run synchronously, in subscription order, without the
+// SessionManager's lock held. The returned function removes the
+// subscription.
+func SubscribeState(listener StateListener) (unsubscribe func()) {
+	stateSubsMu.Lock()
+	stateSubsID++
+	id := stateSubsID
+	stateSubs = append(stateSubs, stateSubscription{id: id, listener: listener})
+	stateSubsMu.Unlock()
+
+	return func() {
+		stateSubsMu.Lock()
+		defer stateSubsMu.Unlock()
+		for i, sub := range stateSubs {
+			if sub.id == id {
+				stateSubs = append(stateSubs[:i:i], stateSubs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// publishState logs change and calls every state listener
+func publishState(change StateChange) {
+	label := sessionLabel(change.ID, change.Primary, change.Account)
+	fmt.Printf("%s🔀 %s: %s → %s (%s)%s\n", ColorCyan, label, change.From, change.To, change.Reason, ColorReset)
+
+	stateSubsMu.RLock()
+	subs := make([]stateSubscription, len(stateSubs))
+	copy(subs, stateSubs)
+	stateSubsMu.RUnlock()
+
+	for _, sub := range subs {
+		sub.listener(change)
+	}
+}
+
+// transition moves session to state to. sm.mu must be held; the returned
+// change has to be published with publishState after unlocking, and is nil
+// when nothing changed or the transition isn't allowed.
+func (sm *SessionManager) transition(session *ManagedSession, to SessionState, reason string) *StateChange {
+	from := session.state
+	if from == to {
+		return nil
+	}
+	if !CanTransition(from, to) {
+		fmt.Printf("%s⚠️ %s: perpindahan status %s → %s ditolak (%s)%s\n", ColorYellow, session.label(), from, to, reason, ColorReset)
+		return nil
+	}
+	session.state = to
+	return &StateChange{
+		ID:      session.ID,
+		Primary: session.Primary,
+		Account: session.Account,
+		From:    from,
+		To:      to,
+		Reason:  reason,
+		At:      time.Now(),
+	}
+}
+
+// SetState moves a session to another state. It returns an error if the
+// session isn't managed or the transition isn't allowed.
+func (sm *SessionManager) SetState(id string, to SessionState, reason string) error {
+	sm.mu.Lock()
+	session, exists := sm.sessions[id]
+	if !exists {
+		sm.mu.Unlock()
+		return fmt.Errorf("session %s tidak dikelola", id)
+	}
+	from := session.state
+	change := sm.transition(session, to, reason)
+	sm.mu.Unlock()
+
+	if change == nil && from != to {
+		return fmt.Errorf("session %s tidak bisa berpindah dari %s ke %s", id, from, to)
+	}
+	if change != nil {
+		publishState(*change)
+	}
+	return nil
+}
+
+// State returns the state of a managed session
+func (sm *SessionManager) State(id string) (SessionState, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	session, exists := sm.sessions[id]
+	if !exists {
+		return StateRemoved, false
+	}
+	return session.state, true
+}
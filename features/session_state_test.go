@@ -0,0 +1,48 @@
+package features
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to SessionState
+		want     bool
+	}{
+		{StatePending, StatePairing, true},
+		{StatePending, StateConnecting, true},
+		{StatePairing, StateConnected, true},
+		{StatePairing, StateLoggedOut, true},
+		{StateConnecting, StateBackoff, true},
+		{StateConnected, StateBackoff, true},
+		{StateConnected, StatePaused, true},
+		{StateBackoff, StateConnecting, true},
+		{StatePaused, StateConnecting, true},
+		{StateLoggedOut, StateConnecting, true},
+		{StateConnected, StateConnected, true},
+		{StateRemoved, StateRemoved, true},
+
+		{StateLoggedOut, StateConnected, false},
+		{StateLoggedOut, StatePaused, false},
+		{StatePaused, StateConnected, false},
+		{StatePaused, StateBackoff, false},
+		{StatePairing, StatePaused, false},
+		{StatePairing, StateBackoff, false},
+		{StateConnected, StatePairing, false},
+		{StateConnected, StatePending, false},
+		{StateBackoff, StatePairing, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// Every state can be removed, and removed is final
+	for state := range stateNames {
+		if !CanTransition(state, StateRemoved) {
+			t.Errorf("CanTransition(%v, removed) = false, want true", state)
+		}
+		if state != StateRemoved && CanTransition(StateRemoved, state) {
+			t.Errorf("CanTransition(removed, %v) = true, want false", state)
+		}
+	}
+}
@@ -0,0 +1,719 @@
+package features
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/utils"
+)
+
+// SessionMetrics counts what happened to one account since it was added to
+// the SessionManager
+type SessionMetrics struct {
+	Connects          int
+	Disconnects       int
+	ReconnectAttempts int
+	HealthFailures    int
+	Messages          int
+	AddedAt           time.Time
+	LastConnected     time.Time
+	LastDisconnected  time.Time
+}
+
+// ManagedSession is one WhatsApp account run by the SessionManager: the
+// main bot (Primary) or a jadibot. Reconnects, health checks, checkpoints,
+// metrics, message routing and the connection state (SessionState) are the
+// same for both; the owner only decides what giving up on the session
+// means.
+type ManagedSession struct {
+	// ID is the account's phone number
+	ID      string
+	Primary bool
+	// Account is the main account a jadibot belongs to; a primary session
+	// is its own account
+	Account   string
+	Client    *whatsmeow.Client
+	Container *sqlstore.Container
+	// DBPath is the session database file, checkpointed periodically
+	DBPath string
+	// OnGiveUp is called when the session can't be recovered: it was logged
+	// out, reconnecting failed reconnect.max_attempts times or the health
+	// check failed health_check.max_fails times. Without it the session is
+	// simply retried on every health check, as the primary session is.
+	OnGiveUp func(reason string)
+	// OnError is told about every disconnect and failed reconnect
+	OnError func(reason string)
+	// OnNotice tells the owner about logouts, bans, an outdated client and
+	// other failures that need their attention
+	OnNotice func(notice Notice)
+	// OnLoggedOut cleans up a session whose device was unlinked and starts
+	// pairing again. Without it a logout gives up on the session.
+	OnLoggedOut func(reason string)
+
+	state SessionState
+	// ctx is cancelled when the session is removed, which stops every
+	// goroutine working for it
+	ctx    context.Context
+	cancel context.CancelFunc
+	// reconnectCancel stops the running reconnect loop; nil when none runs
+	reconnectCancel context.CancelFunc
+	failCount       int
+	lastFailTime    time.Time
+	// attemptFailures counts failed connection attempts since the session
+	// was last connected; lastError is the most recent one
+	attemptFailures int
+	lastError       string
+	// nextRetry is when the next reconnect attempt is due, zero if none
+	nextRetry time.Time
+	// circuitUntil is set while the circuit is open: after a whole
+	// reconnect run failed, nothing is retried before it
+	circuitUntil time.Time
+	// bannedUntil is set while WhatsApp bans the account; automation is on
+	// hold and nothing is retried before it
+	bannedUntil time.Time
+	// noticed holds the notice kinds sent during the current outage
+	noticed map[NoticeKind]bool
+	metrics SessionMetrics
+}
+
+// label names the session in logs
+func (s *ManagedSession) label() string {
+	return sessionLabel(s.ID, s.Primary, s.Account)
+}
+
+// sessionLabel names a session in logs, tagged with its main account in
+// multi-account mode
+func sessionLabel(id string, primary bool, account string) string {
+	if primary {
+		return core.AccountTag(id) + "bot utama " + id
+	}
+	return core.AccountTag(account) + "jadibot " + id
+}
+
+// resetFailures clears the failure counters and closes the circuit after
+// the session connected. sm.mu must be held.
+func (s *ManagedSession) resetFailures() {
+	s.failCount = 0
+	s.attemptFailures = 0
+	s.nextRetry = time.Time{}
+	s.circuitUntil = time.Time{}
+	s.bannedUntil = time.Time{}
+	s.noticed = nil
+}
+
+// circuitOpen reports whether reconnects are held back at now, after a
+// failed reconnect run or while WhatsApp rejects the client. sm.mu must be
+// held.
+func (s *ManagedSession) circuitOpen(now time.Time) bool {
+	return now.Before(s.circuitUntil)
+}
+
+// recordReconnectFailure counts a failed reconnect attempt. After the last
+// attempt of a run the circuit opens for cooldown. sm.mu must be held.
+func (s *ManagedSession) recordReconnectFailure(err error, lastAttempt bool, cooldown time.Duration) {
+	s.failCount++
+	s.attemptFailures++
+	s.lastFailTime = time.Now()
+	s.lastError = err.Error()
+	if lastAttempt {
+		s.circuitUntil = s.lastFailTime.Add(cooldown)
+		s.nextRetry = s.circuitUntil
+	}
+}
+
+// Context is cancelled when the session is removed. Goroutines working for
+// the session should stop when it is done.
+func (s *ManagedSession) Context() context.Context {
+	return s.ctx
+}
+
+// SessionStatus is a copy of a managed session's state
+type SessionStatus struct {
+	ID           string
+	Primary      bool
+	Account      string
+	State        SessionState
+	Connected    bool
+	FailCount    int
+	LastFailTime time.Time
+	// AttemptFailures, LastError, NextRetry and CircuitUntil describe the
+	// reconnect supervisor; see ManagedSession
+	AttemptFailures int
+	LastError       string
+	NextRetry       time.Time
+	CircuitUntil    time.Time
+	BannedUntil     time.Time
+	Metrics         SessionMetrics
+}
+
+// CircuitOpen reports whether reconnects are suspended
+func (s SessionStatus) CircuitOpen() bool {
+	return time.Now().Before(s.CircuitUntil)
+}
+
+// SessionManager owns the whatsmeow client of every account the bot runs
+type SessionManager struct {
+	mu       sync.RWMutex
+	sessions map[string]*ManagedSession
+	// messageHandler receives every non-story message of every session
+	messageHandler func(session *ManagedSession, msg *events.Message)
+	// healthTasks run after every health check, e.g. jadibot expiry
+	healthTasks []func()
+	healthOnce  sync.Once
+}
+
+var sessionManager = &SessionManager{
+	sessions: make(map[string]*ManagedSession),
+}
+
+func GetSessionManager() *SessionManager {
+	return sessionManager
+}
+
+// SessionDBURI returns the SQLite URI of a session database
+func SessionDBURI(path string) string {
+	return fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(FULL)&_pragma=wal_autocheckpoint(100)", path)
+}
+
+// Add starts managing a session in the pending state, or connected if its
+// client already is. Its owner must pass the client's events to
+// HandleEvent and move it on with SetState.
+func (sm *SessionManager) Add(session *ManagedSession) error {
+	sm.mu.Lock()
+	if _, exists := sm.sessions[session.ID]; exists {
+		sm.mu.Unlock()
+		return fmt.Errorf("session %s sudah berjalan", session.ID)
+	}
+	session.ctx, session.cancel = context.WithCancel(context.Background())
+	if session.Primary {
+		session.Account = session.ID
+	}
+	// Reconnect is the only reconnect supervisor; whatsmeow's own would
+	// retry alongside it without backoff
+	if session.Client != nil {
+		session.Client.EnableAutoReconnect = false
+	}
+	session.state = StatePending
+	session.metrics = SessionMetrics{AddedAt: time.Now()}
+	var change *StateChange
+	// Sessions connected before they are added count that connection
+	if session.Client != nil && session.Client.IsConnected() {
+		session.metrics.Connects = 1
+		session.metrics.LastConnected = session.metrics.AddedAt
+		change = sm.transition(session, StateConnected, "sudah terhubung saat ditambahkan")
+	}
+	sm.sessions[session.ID] = session
+	sm.mu.Unlock()
+
+	if change != nil {
+		publishState(*change)
+	}
+	return nil
+}
+
+// Remove stops managing a session: it moves to removed, its context is
+// cancelled, its client disconnected and its database closed. Its files are
+// left to the owner.
+func (sm *SessionManager) Remove(id string) {
+	sm.mu.Lock()
+	session, exists := sm.sessions[id]
+	if !exists {
+		sm.mu.Unlock()
+		return
+	}
+	change := sm.transition(session, StateRemoved, "dihapus")
+	delete(sm.sessions, id)
+	session.reconnectCancel = nil
+	sm.mu.Unlock()
+
+	session.cancel()
+	if change != nil {
+		publishState(*change)
+	}
+	if session.Client != nil {
+		session.Client.Disconnect()
+	}
+	if session.Container != nil {
+		session.Container.Close()
+	}
+}
+
+// Get returns a managed session, or nil
+func (sm *SessionManager) Get(id string) *ManagedSession {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.sessions[id]
+}
+
+func (sm *SessionManager) status(session *ManagedSession) SessionStatus {
+	status := SessionStatus{
+		ID:              session.ID,
+		Primary:         session.Primary,
+		Account:         session.Account,
+		State:           session.state,
+		FailCount:       session.failCount,
+		LastFailTime:    session.lastFailTime,
+		AttemptFailures: session.attemptFailures,
+		LastError:       session.lastError,
+		NextRetry:       session.nextRetry,
+		CircuitUntil:    session.circuitUntil,
+		BannedUntil:     session.bannedUntil,
+		Metrics:         session.metrics,
+	}
+	if session.Client != nil {
+		status.Connected = session.Client.IsConnected()
+	}
+	return status
+}
+
+// Status returns the state of one session
+func (sm *SessionManager) Status(id string) (SessionStatus, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	session, exists := sm.sessions[id]
+	if !exists {
+		return SessionStatus{}, false
+	}
+	return sm.status(session), true
+}
+
+// All returns the state of every session, primary first
+func (sm *SessionManager) All() []SessionStatus {
+	sm.mu.RLock()
+	statuses := make([]SessionStatus, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		statuses = append(statuses, sm.status(session))
+	}
+	sm.mu.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Primary != statuses[j].Primary {
+			return statuses[i].Primary
+		}
+		return statuses[i].ID < statuses[j].ID
+	})
+	return statuses
+}
+
+// SetPaused moves a session to paused, where it is not reconnected or
+// health checked, stopping a running reconnect loop. Unpausing moves it to
+// connecting; the caller connects the client.
+func (sm *SessionManager) SetPaused(id string, paused bool) error {
+	sm.mu.Lock()
+	session, exists := sm.sessions[id]
+	if !exists {
+		sm.mu.Unlock()
+		return fmt.Errorf("session %s tidak dikelola", id)
+	}
+	to, reason := StateConnecting, "dilanjutkan"
+	if paused {
+		to, reason = StatePaused, "dijeda"
+	}
+	from := session.state
+	change := sm.transition(session, to, reason)
+	if change == nil && from != to {
+		sm.mu.Unlock()
+		return fmt.Errorf("session %s tidak bisa berpindah dari %s ke %s", id, from, to)
+	}
+	if paused && session.reconnectCancel != nil {
+		session.reconnectCancel()
+		session.reconnectCancel = nil
+	}
+	session.failCount = 0
+	sm.mu.Unlock()
+
+	if change != nil {
+		publishState(*change)
+	}
+	return nil
+}
+
+// SetMessageHandler sets the function that routes messages of every
+// session, other than stories
+func (sm *SessionManager) SetMessageHandler(handler func(session *ManagedSession, msg *events.Message)) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.messageHandler = handler
+}
+
+// OnHealthCheck adds a task to run after every health check
+func (sm *SessionManager) OnHealthCheck(task func()) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.healthTasks = append(sm.healthTasks, task)
+}
+
+// HandleEvent is the shared part of every session's event handling: metrics,
+// state transitions, reconnects and message routing. Events of clients that
+// aren't managed are ignored.
+func (sm *SessionManager) HandleEvent(id string, client *whatsmeow.Client, evt interface{}) {
+	session := sm.Get(id)
+	if session == nil || session.Client != client {
+		return
+	}
+	if sm.handleFailureEvent(session, evt) {
+		return
+	}
+
+	sm.mu.Lock()
+	if sm.sessions[id] != session {
+		sm.mu.Unlock()
+		return
+	}
+	handler := sm.messageHandler
+	reconnect := false
+
+	var change *StateChange
+	switch evt.(type) {
+	case *events.PairSuccess:
+		change = sm.transition(session, StateConnecting, "pairing berhasil")
+	case *events.Connected:
+		session.metrics.Connects++
+		session.metrics.LastConnected = time.Now()
+		session.resetFailures()
+		change = sm.transition(session, StateConnected, "terhubung")
+	case *events.Disconnected:
+		session.metrics.Disconnects++
+		session.metrics.LastDisconnected = time.Now()
+		// Disconnects while pairing, connecting or paused are expected or
+		// handled by whoever is connecting
+		if session.state == StateConnected {
+			change = sm.transition(session, StateBackoff, "koneksi terputus")
+			reconnect = true
+		}
+	case *events.StreamReplaced:
+		if session.state == StateConnected {
+			change = sm.transition(session, StateBackoff, "stream diganti koneksi lain")
+			reconnect = true
+		}
+	case *events.Message:
+		session.metrics.Messages++
+	}
+	state := session.state
+	banned := time.Now().Before(session.bannedUntil)
+	sm.mu.Unlock()
+
+	if change != nil {
+		publishState(*change)
+	}
+
+	switch v := evt.(type) {
+	case *events.Connected:
+		go utils.CacheAllJoinedGroupsMappings(client)
+
+	case *events.Disconnected, *events.StreamReplaced:
+		if !reconnect {
+			return
+		}
+		if session.OnError != nil {
+			session.OnError(change.Reason)
+		}
+		go sm.Reconnect(id)
+
+	case *events.Message:
+		if state == StatePaused || banned || handler == nil || v.Info.Chat.Server == types.BroadcastServer {
+			return
+		}
+		handler(session, v)
+	}
+}
+
+// giveUp hands an unrecoverable session to its owner
+func (sm *SessionManager) giveUp(session *ManagedSession, reason string) {
+	if session.OnGiveUp == nil {
+		fmt.Printf("%s❌ %s: %s - akan dicoba lagi saat health check%s\n", ColorYellow, session.label(), reason, ColorReset)
+		return
+	}
+	session.OnGiveUp(reason)
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Reconnect is the reconnect supervisor of a session: only one runs per
+// session at a time. It retries up to reconnect.max_attempts times with
+// exponential backoff and full jitter, moving between backoff and
+// connecting, and stops when the session is paused or removed. When every
+// attempt fails the circuit opens and no reconnect starts until
+// reconnect.circuit_cooldown has passed.
+func (sm *SessionManager) Reconnect(id string) {
+	sm.mu.Lock()
+	session, exists := sm.sessions[id]
+	if !exists || session.reconnectCancel != nil || session.circuitOpen(time.Now()) {
+		sm.mu.Unlock()
+		return
+	}
+	switch session.state {
+	case StateConnected, StateConnecting, StateBackoff, StateLoggedOut:
+	default:
+		sm.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(session.ctx)
+	session.reconnectCancel = cancel
+	change := sm.transition(session, StateBackoff, "menunggu reconnect")
+	sm.mu.Unlock()
+
+	if change != nil {
+		publishState(*change)
+	}
+	defer func() {
+		sm.mu.Lock()
+		if ctx.Err() == nil {
+			session.reconnectCancel = nil
+		}
+		sm.mu.Unlock()
+		cancel()
+	}()
+
+	reconnect := core.Runtime().Reconnect
+	maxRetries := reconnect.MaxAttempts
+	client := session.Client
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		delay := reconnect.Delay(attempt)
+		sm.mu.Lock()
+		session.nextRetry = time.Now().Add(delay)
+		sm.mu.Unlock()
+		if !sleepContext(ctx, delay) {
+			return
+		}
+
+		sm.mu.Lock()
+		session.nextRetry = time.Time{}
+		if client.IsConnected() {
+			session.resetFailures()
+			change := sm.transition(session, StateConnected, "sudah terhubung kembali")
+			sm.mu.Unlock()
+			if change != nil {
+				publishState(*change)
+			}
+			return
+		}
+		session.metrics.ReconnectAttempts++
+		sm.mu.Unlock()
+
+		if client.Store.ID == nil {
+			sm.giveUp(session, "session tidak lagi memiliki device ID (kemungkinan logout dari HP)")
+			return
+		}
+
+		if err := sm.SetState(id, StateConnecting, fmt.Sprintf("percobaan reconnect %d/%d", attempt, maxRetries)); err != nil {
+			return
+		}
+		err := client.ConnectContext(ctx)
+		if err == nil {
+			if !sleepContext(ctx, 3*time.Second) {
+				return
+			}
+			if !client.IsConnected() {
+				err = errors.New("masih tidak terhubung setelah reconnect")
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			fmt.Printf("%s⚠️ Gagal reconnect %s (percobaan %d/%d): %v%s\n", ColorYellow, session.label(), attempt, maxRetries, err, ColorReset)
+			if session.OnError != nil {
+				session.OnError(fmt.Sprintf("gagal reconnect: %v", err))
+			}
+
+			sm.mu.Lock()
+			session.recordReconnectFailure(err, attempt == maxRetries, reconnect.CircuitCooldown)
+			change := sm.transition(session, StateBackoff, fmt.Sprintf("gagal reconnect: %v", err))
+			circuitUntil := session.circuitUntil
+			sm.mu.Unlock()
+			if change != nil {
+				publishState(*change)
+			}
+
+			if attempt == maxRetries {
+				reason := fmt.Sprintf("gagal reconnect %d kali berturut-turut (error terakhir: %v), dicoba lagi %s", maxRetries, err, FormatStartDateTime(circuitUntil))
+				fmt.Printf("%s🔌 %s: circuit terbuka - %s%s\n", ColorYellow, session.label(), reason, ColorReset)
+				if session.OnError != nil {
+					session.OnError(reason)
+				}
+				return
+			}
+			continue
+		}
+
+		client.SendPresence(ctx, types.PresenceAvailable)
+		fmt.Printf("%s✅ %s berhasil reconnect%s\n", ColorGreen, session.label(), ColorReset)
+
+		sm.mu.Lock()
+		session.resetFailures()
+		change := sm.transition(session, StateConnected, "reconnect berhasil")
+		sm.mu.Unlock()
+		if change != nil {
+			publishState(*change)
+		}
+		return
+	}
+}
+
+// StartHealthCheck checks every session each health_check.interval and
+// checkpoints their databases each health_check.checkpoint_interval. It
+// only starts once.
+func (sm *SessionManager) StartHealthCheck() {
+	sm.healthOnce.Do(func() {
+		go sm.runHealthCheck()
+	})
+}
+
+func (sm *SessionManager) runHealthCheck() {
+	healthCheck := core.Runtime().HealthCheck
+	ticker := time.NewTicker(healthCheck.Interval)
+	checkpointTicker := time.NewTicker(healthCheck.CheckpointInterval)
+	defer ticker.Stop()
+	defer checkpointTicker.Stop()
+
+	fmt.Printf("%s🔍 Health check dimulai (interval: %v)%s\n", ColorCyan, healthCheck.Interval, ColorReset)
+
+	for {
+		select {
+		case <-ticker.C:
+			sm.checkAll()
+		case <-checkpointTicker.C:
+			sm.CheckpointAll()
+		}
+	}
+}
+
+// checkAll counts a failure for every disconnected session and reconnects
+// it, giving up after health_check.max_fails. Sessions whose circuit is open
+// are left alone: the failed run that opened it was already counted.
+func (sm *SessionManager) checkAll() {
+	healthCheck := core.Runtime().HealthCheck
+
+	type giveUp struct {
+		session *ManagedSession
+		reason  string
+	}
+	var giveUps []giveUp
+	var reconnects []string
+	var changes []StateChange
+
+	sm.mu.Lock()
+	for id, session := range sm.sessions {
+		// Pairing and paused sessions are offline on purpose, removed ones
+		// are on their way out and reconnect loops check themselves. Banned
+		// sessions wait for the ban to end without counting failures.
+		switch session.state {
+		case StatePending, StatePairing, StatePaused, StateRemoved:
+			continue
+		}
+		if time.Now().Before(session.bannedUntil) {
+			continue
+		}
+		if session.reconnectCancel != nil {
+			continue
+		}
+		if session.Client == nil {
+			giveUps = append(giveUps, giveUp{session, "session tidak memiliki client"})
+			continue
+		}
+		if session.Client.IsConnected() {
+			session.resetFailures()
+			if change := sm.transition(session, StateConnected, "health check: terhubung"); change != nil {
+				changes = append(changes, *change)
+			}
+			continue
+		}
+		if session.Client.Store == nil || session.Client.Store.ID == nil {
+			giveUps = append(giveUps, giveUp{session, "session tidak lagi memiliki device ID (kemungkinan logout dari HP)"})
+			continue
+		}
+
+		if session.circuitOpen(time.Now()) {
+			continue
+		}
+
+		if session.failCount > 0 && time.Since(session.lastFailTime) > healthCheck.FailReset {
+			session.failCount = 0
+		}
+
+		session.failCount++
+		session.lastFailTime = time.Now()
+		session.metrics.HealthFailures++
+		if session.failCount >= healthCheck.MaxFails && session.OnGiveUp != nil {
+			fmt.Printf("%s❌ [Health Check] %s gagal terhubung %d kali berturut-turut%s\n", ColorYellow, session.label(), session.failCount, ColorReset)
+			giveUps = append(giveUps, giveUp{session, fmt.Sprintf("health check: gagal terhubung %d kali berturut-turut", session.failCount)})
+			continue
+		}
+		fmt.Printf("%s⚠️ [Health Check] %s tidak terhubung (fail count: %d/%d), memulai reconnect...%s\n", ColorYellow, session.label(), session.failCount, healthCheck.MaxFails, ColorReset)
+		reconnects = append(reconnects, id)
+	}
+	tasks := append([]func(){}, sm.healthTasks...)
+	sm.mu.Unlock()
+
+	for _, change := range changes {
+		publishState(change)
+	}
+	for _, g := range giveUps {
+		sm.giveUp(g.session, g.reason)
+	}
+	for _, id := range reconnects {
+		go sm.Reconnect(id)
+	}
+	for _, task := range tasks {
+		task()
+	}
+}
+
+// CheckpointAll truncates the WAL of every session database
+func (sm *SessionManager) CheckpointAll() {
+	sm.mu.RLock()
+	sessions := make([]*ManagedSession, 0, len(sm.sessions))
+	for _, session := range sm.sessions {
+		sessions = append(sessions, session)
+	}
+	sm.mu.RUnlock()
+
+	for _, session := range sessions {
+		if session.DBPath == "" {
+			continue
+		}
+		if err := CheckpointSessionDB(session.DBPath); err != nil {
+			fmt.Printf("%s⚠️ Gagal checkpoint database %s: %v%s\n", ColorYellow, session.label(), err, ColorReset)
+		}
+	}
+}
+
+// CheckpointSessionDB truncates the WAL of one session database
+func CheckpointSessionDB(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec("PRAGMA wal_checkpoint(TRUNCATE);")
+	return err
+}
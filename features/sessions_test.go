@@ -0,0 +1,81 @@
+package features
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+
+	"whatsapp-bot/core"
+)
+
+func TestReconnectCircuit(t *testing.T) {
+	const attempts = 3
+	cooldown := 10 * time.Minute
+	session := &ManagedSession{}
+	err := errors.New("timeout")
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		session.recordReconnectFailure(err, attempt == attempts, cooldown)
+		if open := session.circuitOpen(time.Now()); open != (attempt == attempts) {
+			t.Errorf("after attempt %d/%d circuitOpen = %v", attempt, attempts, open)
+		}
+	}
+	if session.failCount != attempts || session.attemptFailures != attempts || session.lastError != "timeout" {
+		t.Errorf("failCount = %d, attemptFailures = %d, lastError = %q", session.failCount, session.attemptFailures, session.lastError)
+	}
+	if !session.nextRetry.Equal(session.circuitUntil) {
+		t.Errorf("nextRetry = %v, want the end of the circuit %v", session.nextRetry, session.circuitUntil)
+	}
+
+	// The circuit closes by itself once the cooldown has passed...
+	if session.circuitOpen(session.circuitUntil.Add(time.Second)) {
+		t.Errorf("circuit still open after its cooldown")
+	}
+	// ...or right away when the session connects
+	session.resetFailures()
+	if session.circuitOpen(time.Now()) || session.failCount != 0 || session.attemptFailures != 0 {
+		t.Errorf("resetFailures left circuit open or failures counted: %+v", session)
+	}
+}
+
+// disconnectedSession is a registered device whose client is not connected
+func disconnectedSession(id string, state SessionState) *ManagedSession {
+	jid := types.NewJID(id, types.DefaultUserServer)
+	return &ManagedSession{
+		ID:     id,
+		Client: &whatsmeow.Client{Store: &store.Device{ID: &jid}},
+		state:  state,
+	}
+}
+
+func TestCircuitHoldsBackReconnects(t *testing.T) {
+	maxFails := core.Runtime().HealthCheck.MaxFails
+	session := disconnectedSession("6281234567890", StateBackoff)
+	gaveUp := false
+	session.OnGiveUp = func(reason string) { gaveUp = true }
+	// One more counted failure would give up on the session
+	session.failCount = maxFails - 1
+	session.lastFailTime = time.Now()
+	session.circuitUntil = time.Now().Add(time.Hour)
+
+	sm := &SessionManager{sessions: map[string]*ManagedSession{session.ID: session}}
+
+	for i := 0; i < 3; i++ {
+		sm.checkAll()
+	}
+	if gaveUp || session.failCount != maxFails-1 {
+		t.Errorf("health check during an open circuit: gaveUp = %v, failCount = %d, want false, %d", gaveUp, session.failCount, maxFails-1)
+	}
+	if session.reconnectCancel != nil || session.state != StateBackoff {
+		t.Errorf("health check started a reconnect during an open circuit")
+	}
+
+	sm.Reconnect(session.ID)
+	if session.reconnectCancel != nil || session.state != StateBackoff {
+		t.Errorf("Reconnect ran during an open circuit")
+	}
+}
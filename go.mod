@@ -8,6 +8,8 @@ require (
 	github.com/nyaruka/phonenumbers v1.6.7
 	go.mau.fi/whatsmeow v0.0.0-20251120135021-071293c6b9f0
 	google.golang.org/protobuf v1.36.10
+	gopkg.in/yaml.v3 v3.0.1
+	rsc.io/qr v0.2.0
 )
 
 require (
@@ -31,5 +33,4 @@ require (
 	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/term v0.37.0 // indirect
 	golang.org/x/text v0.31.0 // indirect
-	rsc.io/qr v0.2.0 // indirect
 )
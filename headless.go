@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-bot/core"
+	"whatsapp-bot/features"
+)
+
+// headlessFail ends a headless start that is missing required input. It
+// exits with status 2, like an invalid config, so supervisors don't treat
+// it as a crash worth restarting in a loop.
+func headlessFail(format string, args ...interface{}) {
+	fmt.Printf(ColorReset+ColorBold+ColorYellow+"❌ Mode headless: "+format+ColorReset+"\n", args...)
+	os.Exit(2)
+}
+
+// headlessStartup resolves the main bot's number and pairing method without
+// reading stdin. The number comes from phone_number or, if there is exactly
+// one, the existing session; the pairing method is only required when the
+// session has to be paired.
+func headlessStartup() (string, int) {
+	nomer := core.Runtime().PhoneNumber
+	if nomer != "" {
+		nomer = normalizeMainNumber(nomer)
+		fmt.Print(ColorGreen + "✅ Nomor dari konfigurasi: " + ColorReset + ColorBold + nomer + ColorReset + "\n")
+	} else {
+		existingNumbers := getExistingPhoneNumbers()
+		switch len(existingNumbers) {
+		case 0:
+			headlessFail("nomor bot belum diatur. Isi phone_number di config, env WHATSAPP_NUMBER atau --number")
+		case 1:
+			nomer = existingNumbers[0]
+			fmt.Print(ColorGreen + "✅ Menggunakan session yang ada: " + ColorReset + ColorBold + nomer + ColorReset + "\n")
+		default:
+			headlessFail("ditemukan %d session (%s). Pilih salah satu lewat phone_number, env WHATSAPP_NUMBER atau --number",
+				len(existingNumbers), strings.Join(existingNumbers, ", "))
+		}
+	}
+
+	fmt.Print(ColorCyan + "🔍 Mengecek validitas session...\n" + ColorReset)
+	sessionValid := isSessionValid(nomer)
+	if sessionValid {
+		fmt.Print(ColorGreen + "✅ Session valid!\n" + ColorReset)
+	} else if _, err := os.Stat(core.GetPaths().MainSessionDB(nomer)); err == nil {
+		cleanInvalidSession(nomer)
+	}
+
+	pairingMethod := core.Runtime().PairingMethod
+	switch {
+	case pairingMethod != 0:
+	case sessionValid:
+		// Only used again if the session logs out later
+		pairingMethod = 2
+	default:
+		headlessFail("session %s perlu pairing tapi metode belum diatur. Isi pairing_method (1 = kode, 2 = QR) di config, env WHATSAPP_PAIRING_METHOD atau --pairing", nomer)
+	}
+
+	methodName := "QR Code"
+	if pairingMethod == 1 {
+		methodName = "Kode Pairing"
+	}
+	fmt.Print(ColorGreen + "✅ Metode Pairing: " + ColorReset + ColorBold + methodName + ColorReset + "\n\n")
+	return nomer, pairingMethod
+}
+
+// pairingOutput publishes the main bots' current pairing code or QR outside
+// the terminal in headless mode: to a file and, with pairing_addr, over
+// local HTTP. Both are cleared once pairing succeeds. In multi-account mode
+// each account gets its own file (the number is added to the name) and
+// path (/<nomor>).
+type pairingOutput struct {
+	mu      sync.RWMutex
+	entries map[string]*pairingEntry
+}
+
+type pairingEntry struct {
+	code      string
+	png       []byte
+	updatedAt time.Time
+	file      string
+}
+
+var pairing = &pairingOutput{entries: make(map[string]*pairingEntry)}
+
+func (p *pairingOutput) setCode(number, code string) {
+	if !core.Runtime().Headless {
+		return
+	}
+	p.set(number, code, nil, core.GetPaths().PairingCode())
+}
+
+func (p *pairingOutput) setQR(number, code string) {
+	if !core.Runtime().Headless {
+		return
+	}
+	png, err := features.RenderQRPNG(code)
+	if err != nil {
+		fmt.Printf("%s⚠️ %sGagal membuat gambar QR: %v%s\n", ColorYellow, core.AccountTag(number), err, ColorReset)
+		return
+	}
+	p.set(number, "", png, core.GetPaths().PairingQR())
+}
+
+// pairingFile is the file an account's pairing is written to
+func pairingFile(number, defaultFile string) string {
+	file := core.Runtime().PairingFile
+	if file == "" {
+		file = defaultFile
+	}
+	if core.MultiAccount() {
+		ext := filepath.Ext(file)
+		file = strings.TrimSuffix(file, ext) + "-" + number + ext
+	}
+	return file
+}
+
+func (p *pairingOutput) set(number, code string, png []byte, defaultFile string) {
+	file := pairingFile(number, defaultFile)
+	data := png
+	if png == nil {
+		data = []byte(code + "\n")
+	}
+
+	p.mu.Lock()
+	previous := p.entries[number]
+	p.entries[number] = &pairingEntry{code: code, png: png, updatedAt: time.Now(), file: file}
+	p.mu.Unlock()
+
+	// Switching between code and QR leaves the other file behind otherwise
+	if previous != nil && previous.file != file {
+		os.Remove(previous.file)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		fmt.Printf("%s⚠️ %sGagal menulis file pairing %s: %v%s\n", ColorYellow, core.AccountTag(number), file, err, ColorReset)
+		return
+	}
+	fmt.Printf("%s📄 %sPairing ditulis ke %s%s\n", ColorGreen, core.AccountTag(number), file, ColorReset)
+}
+
+func (p *pairingOutput) clear(number string) {
+	p.mu.Lock()
+	entry := p.entries[number]
+	delete(p.entries, number)
+	p.mu.Unlock()
+
+	if entry != nil {
+		os.Remove(entry.file)
+	}
+}
+
+// ServeHTTP shows an account's pairing QR as PNG or the code as text, or
+// 404 when nothing is waiting to be paired. "/" serves the only waiting
+// pairing, or lists the waiting accounts when there are several.
+func (p *pairingOutput) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	number := strings.Trim(r.URL.Path, "/")
+
+	p.mu.RLock()
+	var waiting []string
+	for n := range p.entries {
+		waiting = append(waiting, n)
+	}
+	if number == "" && len(waiting) == 1 {
+		number = waiting[0]
+	}
+	var entry pairingEntry
+	if e := p.entries[number]; e != nil {
+		entry = *e
+	}
+	p.mu.RUnlock()
+
+	w.Header().Set("Cache-Control", "no-store")
+	switch {
+	case entry.png != nil:
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Last-Modified", entry.updatedAt.UTC().Format(http.TimeFormat))
+		w.Write(entry.png)
+	case entry.code != "":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, entry.code)
+	case number == "" && len(waiting) > 1:
+		sort.Strings(waiting)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "Akun yang menunggu pairing:")
+		for _, n := range waiting {
+			fmt.Fprintf(w, "/%s\n", n)
+		}
+	default:
+		http.Error(w, "tidak ada pairing yang menunggu", http.StatusNotFound)
+	}
+}
+
+// servePairing serves the pairing code/QR on addr. It exits if addr can't
+// be listened on, since the pairing would be unreachable.
+func servePairing(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal membuka pairing_addr: " + err.Error() + ColorReset + "\n")
+		os.Exit(1)
+	}
+	server := &http.Server{Handler: pairing, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	fmt.Printf("%s🌐 Kode pairing/QR tersedia di http://%s/%s\n", ColorGreen, listener.Addr(), ColorReset)
+}
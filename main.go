@@ -2,7 +2,7 @@ package main
 
 import (
         "context"
-        "database/sql"
+        "flag"
         "fmt"
         "os"
         "os/signal"
@@ -13,14 +13,11 @@ import (
         "github.com/mdp/qrterminal/v3"
         _ "github.com/ncruces/go-sqlite3/driver"
         _ "github.com/ncruces/go-sqlite3/embed"
-        "github.com/nyaruka/phonenumbers"
         "go.mau.fi/whatsmeow"
-        waProto "go.mau.fi/whatsmeow/binary/proto"
         "go.mau.fi/whatsmeow/store/sqlstore"
         "go.mau.fi/whatsmeow/types"
         "go.mau.fi/whatsmeow/types/events"
         waLog "go.mau.fi/whatsmeow/util/log"
-        "google.golang.org/protobuf/proto"
 
         "whatsapp-bot/commands"
         "whatsapp-bot/core"
@@ -39,13 +36,6 @@ const (
         ColorBold    = "\033[1m"
 )
 
-type Ev struct {
-        ChatPresence *events.ChatPresence
-        Message      *events.Message
-        Receipt      *events.Receipt
-        More         interface{}
-}
-
 var (
         botStartTime time.Time
 )
@@ -85,18 +75,13 @@ func (f *FilteredLogger) Sub(module string) waLog.Logger {
         return &FilteredLogger{logger: f.logger.Sub(module)}
 }
 
-func Connect(nomor string, cb func(conn *whatsmeow.Client, evt Ev), useQR bool) {
+// Connect connects (pairing first if needed) the main bot and hands it to
+// the SessionManager as the primary session, which keeps it connected
+func Connect(nomor string, useQR bool) {
         ctx := context.Background()
 
-        // Buat folder Wilykun dan Wilykun/bossbot untuk bot utama
-        err := os.MkdirAll("Wilykun/bossbot", 0o755)
-        if err != nil {
-                fmt.Println("GoError creating Wilykun/bossbot folder:", err)
-                return
-        }
-
         dbLog := waLog.Stdout("Database", "ERROR", true)
-        dbPath := "file:Wilykun/bossbot/" + nomor + ".db?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(FULL)&_pragma=wal_autocheckpoint(100)"
+        dbPath := features.SessionDBURI(core.GetPaths().MainSessionDB(nomor))
         container, err := sqlstore.New(ctx, "sqlite3", dbPath, dbLog)
         if err != nil {
                 fmt.Println("GoError:", err)
@@ -113,51 +98,66 @@ func Connect(nomor string, cb func(conn *whatsmeow.Client, evt Ev), useQR bool)
 
         var client *whatsmeow.Client
         var reconnectAttempts int
-        maxReconnectDelay := 60 * time.Second
-        reconnectChan := make(chan bool, 10)
+        sm := features.GetSessionManager()
 
         var connectWithRetry func() error
         connectWithRetry = func() error {
                 if client == nil {
                         client = whatsmeow.NewClient(deviceStore, clientLog)
+                        features.GetJadibotManager().SetMainClient(nomor, client)
+
+                        unsubscribe := core.SubscribeConfig(func(change core.ConfigChange) {
+                                // Another account's own setting
+                                if change.Account != "" && change.Account != nomor {
+                                        return
+                                }
+                                if client.IsConnected() {
+                                        go features.SendOnlinePresence(client, core.AccountConfig(nomor).AutoOnline)
+                                }
+                        }, "auto_online")
+
+                        // Managed from the start so pairing and the first
+                        // connection go through the state machine too
+                        if err := sm.Add(&features.ManagedSession{
+                                ID:        nomor,
+                                Primary:   true,
+                                Client:    client,
+                                Container: container,
+                                DBPath:    core.GetPaths().MainSessionDB(nomor),
+                                OnNotice: func(notice features.Notice) {
+                                        features.NotifyOwners(nomor, notice)
+                                },
+                                // The device is gone: drop this client and
+                                // its database and pair again from scratch
+                                OnLoggedOut: func(reason string) {
+                                        fmt.Printf("%s🚫 %sBot utama logout (%s) - session dihapus, memulai pairing ulang...%s\n", ColorYellow, core.AccountTag(nomor), reason, ColorReset)
+                                        unsubscribe()
+                                        sm.Remove(nomor)
+                                        cleanInvalidSession(nomor)
+                                        go Connect(nomor, useQR)
+                                },
+                        }); err != nil {
+                                fmt.Println("GoError:", err)
+                        }
 
+                        // Stories are the main bot's own; reconnects, logouts
+                        // and commands are handled by the SessionManager
                         client.AddEventHandler(func(evt interface{}) {
                                 switch v := evt.(type) {
                                 case *events.Message:
-                                        cb(client, Ev{Message: v})
-                                case *events.Receipt:
-                                        cb(client, Ev{Receipt: v})
-                                case *events.ChatPresence:
-                                        cb(client, Ev{ChatPresence: v})
-                                case *events.LoggedOut:
-                                        fmt.Println("Bot logged out! Reason:", v.Reason)
-                                        if v.OnConnect {
-                                                fmt.Println("Logged out during connection, will retry in 5 seconds...")
-                                                select {
-                                                case reconnectChan <- true:
-                                                default:
-                                                }
-                                        }
-                                case *events.StreamReplaced:
-                                        fmt.Println("Stream replaced, reconnecting in 2 seconds...")
-                                        select {
-                                        case reconnectChan <- true:
-                                        default:
+                                        if v.Info.Chat.Server == types.BroadcastServer && !sm.AutomationPaused(nomor) {
+                                                features.HandleStoryMessage(client, v)
                                         }
-                                case *events.Disconnected:
-                                        fmt.Println("Disconnected from WhatsApp")
-                                case *events.Connected:
-                                        fmt.Println("Successfully connected to WhatsApp")
-                                        reconnectAttempts = 0
-                                        go utils.CacheAllJoinedGroupsMappings(client)
-                                default:
-                                        cb(client, Ev{More: evt})
+                                case *events.PairSuccess:
+                                        pairing.clear(nomor)
                                 }
+                                sm.HandleEvent(nomor, client, evt)
                         })
                 }
 
                 if client.Store.ID == nil {
                         fmt.Println("No session found, pairing device...")
+                        sm.SetState(nomor, features.StatePairing, "belum ada session")
 
                         if useQR {
                                 fmt.Print(ColorBold + ColorGreen + "\n📱 QR CODE METHOD\n" + ColorReset)
@@ -189,6 +189,7 @@ func Connect(nomor string, cb func(conn *whatsmeow.Client, evt Ev), useQR bool)
                                                 }
                                                 qrterminal.GenerateWithConfig(evt.Code, config)
                                                 fmt.Print("\n")
+                                                pairing.setQR(nomor, evt.Code)
                                         } else if evt.Event == "success" {
                                                 fmt.Print(ColorBold + ColorGreen + "\n✅ Pairing dengan QR Code berhasil!\n" + ColorReset)
                                                 break
@@ -211,9 +212,11 @@ func Connect(nomor string, cb func(conn *whatsmeow.Client, evt Ev), useQR bool)
                                         return fmt.Errorf("pairing error: %v", gagal)
                                 }
                                 fmt.Print(formatConnectionMessage(nomor, linkingCode))
+                                pairing.setCode(nomor, linkingCode)
                         }
                 } else {
                         if !client.IsConnected() {
+                                sm.SetState(nomor, features.StateConnecting, "menghubungkan session")
                                 err := client.Connect()
                                 if err != nil {
                                         return fmt.Errorf("connection error: %v", err)
@@ -233,62 +236,21 @@ func Connect(nomor string, cb func(conn *whatsmeow.Client, evt Ev), useQR bool)
                         break
                 }
 
-                reconnectAttempts++
-                delay := time.Duration(reconnectAttempts) * 5 * time.Second
-                if delay > maxReconnectDelay {
-                        delay = maxReconnectDelay
+                // A paired session is retried by its reconnect supervisor;
+                // only pairing is retried here
+                if client != nil && client.Store.ID != nil {
+                        fmt.Printf("%sConnection failed: %v. Handing over to the reconnect supervisor...\n", core.AccountTag(nomor), err)
+                        go sm.Reconnect(nomor)
+                        break
                 }
 
-                fmt.Printf("Connection failed (attempt %d): %v. Retrying in %v...\n", reconnectAttempts, err, delay)
+                reconnectAttempts++
+                delay := core.Runtime().Reconnect.Delay(reconnectAttempts)
+                fmt.Printf("%sConnection failed (attempt %d): %v. Retrying in %v...\n", core.AccountTag(nomor), reconnectAttempts, err, delay)
                 time.Sleep(delay)
         }
 
-        go func() {
-                ticker := time.NewTicker(30 * time.Second)
-                checkpointTicker := time.NewTicker(5 * time.Minute)
-                defer ticker.Stop()
-                defer checkpointTicker.Stop()
-
-                for {
-                        select {
-                        case <-ticker.C:
-                                if client != nil && !client.IsConnected() {
-                                        fmt.Println("Connection lost detected by health check, attempting to reconnect...")
-                                        reconnectAttempts = 0
-                                        for i := 0; i < 5; i++ {
-                                                if err := connectWithRetry(); err == nil {
-                                                        break
-                                                }
-                                                reconnectAttempts++
-                                                delay := time.Duration(reconnectAttempts) * 5 * time.Second
-                                                if delay > maxReconnectDelay {
-                                                        delay = maxReconnectDelay
-                                                }
-                                                fmt.Printf("Reconnection failed (attempt %d), retrying in %v...\n", reconnectAttempts, delay)
-                                                time.Sleep(delay)
-                                        }
-                                }
-                        case <-checkpointTicker.C:
-                                checkpointDatabase(nomor)
-                        case <-reconnectChan:
-                                time.Sleep(3 * time.Second)
-                                fmt.Println("Reconnecting after event trigger...")
-                                reconnectAttempts = 0
-                                for i := 0; i < 5; i++ {
-                                        if err := connectWithRetry(); err == nil {
-                                                break
-                                        }
-                                        reconnectAttempts++
-                                        delay := time.Duration(reconnectAttempts) * 5 * time.Second
-                                        if delay > maxReconnectDelay {
-                                                delay = maxReconnectDelay
-                                        }
-                                        fmt.Printf("Reconnection failed (attempt %d), retrying in %v...\n", reconnectAttempts, delay)
-                                        time.Sleep(delay)
-                                }
-                        }
-                }
-        }()
+        sm.StartHealthCheck()
 }
 
 func parseChoice(input string, maxOptions int) int {
@@ -324,68 +286,19 @@ type CountryInfo struct {
         Flag        string
 }
 
-var countryNameMap = map[string]string{
-        "ID": "🇮🇩 Indonesia",
-        "US": "🇺🇸 United States",
-        "GB": "🇬🇧 United Kingdom",
-        "FR": "🇫🇷 France",
-        "DE": "🇩🇪 Germany",
-        "IT": "🇮🇹 Italy",
-        "ES": "🇪🇸 Spain",
-        "NL": "🇳🇱 Netherlands",
-        "BE": "🇧🇪 Belgium",
-        "CH": "🇨🇭 Switzerland",
-        "AT": "🇦🇹 Austria",
-        "PL": "🇵🇱 Poland",
-        "SE": "🇸🇪 Sweden",
-        "NO": "🇳🇴 Norway",
-        "DK": "🇩🇰 Denmark",
-        "FI": "🇫🇮 Finland",
-        "RU": "🇷🇺 Russia",
-        "UA": "🇺🇦 Ukraine",
-        "TR": "🇹🇷 Turkey",
-        "GR": "🇬🇷 Greece",
-        "PT": "🇵🇹 Portugal",
-        "CZ": "🇨🇿 Czech Republic",
-        "HU": "🇭🇺 Hungary",
-        "RO": "🇷🇴 Romania",
-        "BG": "🇧🇬 Bulgaria",
-        "HR": "🇭🇷 Croatia",
-        "SI": "🇸🇮 Slovenia",
-        "SK": "🇸🇰 Slovakia",
-        "LT": "🇱🇹 Lithuania",
-        "LV": "🇱🇻 Latvia",
-        "EE": "🇪🇪 Estonia",
-        "JP": "🇯🇵 Japan",
-        "KR": "🇰🇷 South Korea",
-        "CN": "🇨🇳 China",
-        "IN": "🇮🇳 India",
-        "TH": "🇹🇭 Thailand",
-        "MY": "🇲🇾 Malaysia",
-        "SG": "🇸🇬 Singapore",
-        "PH": "🇵🇭 Philippines",
-        "VN": "🇻🇳 Vietnam",
-        "BD": "🇧🇩 Bangladesh",
-        "PK": "🇵🇰 Pakistan",
-        "AU": "🇦🇺 Australia",
-        "NZ": "🇳🇿 New Zealand",
-        "CA": "🇨🇦 Canada",
-        "MX": "🇲🇽 Mexico",
-        "BR": "🇧🇷 Brazil",
-        "AR": "🇦🇷 Argentina",
-        "CL": "🇨🇱 Chile",
-        "CO": "🇨🇴 Colombia",
-        "PE": "🇵🇪 Peru",
-        "ZA": "🇿🇦 South Africa",
-        "EG": "🇪🇬 Egypt",
-        "NG": "🇳🇬 Nigeria",
-        "KE": "🇰🇪 Kenya",
+// normalizeMainNumber validates the main bot's number (from config or typed
+// at the prompt) and returns it with its country code, exiting if invalid
+func normalizeMainNumber(input string) string {
+        phone, err := utils.ParsePhoneNumber(input, core.Runtime().DefaultRegion)
+        if err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ " + err.Error() + "\n" + ColorReset)
+                os.Exit(1)
+        }
+        return phone.Number
 }
 
 func getCountryInfo(nomor string) CountryInfo {
-        phoneNumber := "+" + nomor
-
-        parsedNumber, err := phonenumbers.Parse(phoneNumber, "")
+        phone, err := utils.ParsePhoneNumber("+"+nomor, "")
         if err != nil {
                 return CountryInfo{
                         CountryCode: "??",
@@ -394,15 +307,10 @@ func getCountryInfo(nomor string) CountryInfo {
                 }
         }
 
-        countryCode := phonenumbers.GetRegionCodeForNumber(parsedNumber)
-        countryName, exists := countryNameMap[countryCode]
-        if !exists {
-                countryName = "🌍 " + countryCode
-        }
-
         return CountryInfo{
-                CountryCode: countryCode,
-                CountryName: countryName,
+                CountryCode: phone.Region,
+                CountryName: phone.Country(),
+                Flag:        phone.Flag(),
         }
 }
 
@@ -494,295 +402,16 @@ func formatConnectionMessage(nomor string, code string) string {
         return message.String()
 }
 
-func mes(client *whatsmeow.Client, evt Ev) {
-        if evt.Message != nil {
-                v := evt.Message
-
-                if v.Info.Chat.Server == types.BroadcastServer {
-                        features.HandleStoryMessage(client, v)
-                        return
-                }
-
-                features.HandleAutoPresence(client, v)
-
-                ctx := context.Background()
-                botJID := client.Store.ID
-
-                var messageText string
-                if v.Message.GetConversation() != "" {
-                        messageText = v.Message.GetConversation()
-                } else if v.Message.GetExtendedTextMessage() != nil {
-                        messageText = v.Message.GetExtendedTextMessage().GetText()
-                }
-
-                isSelfMode := utils.IsSelfMessage(client, v.Info.Sender) ||
-                        v.Info.Sender.User == botJID.User ||
-                        v.Info.Chat.User == botJID.User ||
-                        v.Info.IsFromMe
-
-                if utils.IsGroupJID(v.Info.Chat.String()) {
-                        utils.CacheGroupParticipantMappings(client, v.Info.Chat)
-                }
-
-                if isSelfMode {
-                        cmd, args, isCmd := commands.ParseCommand(messageText)
-                        if isCmd {
-                                switch cmd {
-                        case "bot":
-                                replyMsg := &waProto.Message{
-                                        ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                Text: proto.String("AKTIF bang"),
-                                                ContextInfo: &waProto.ContextInfo{
-                                                        StanzaID:    proto.String(v.Info.ID),
-                                                        Participant: proto.String(v.Info.Sender.String()),
-                                                },
-                                        },
-                                }
-
-                                _, err := client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                if err != nil {
-                                        fmt.Printf("%s⚠️ Failed to send BOT response: %v%s\n", ColorYellow, err, ColorReset)
-                                } else {
-                                        fmt.Printf("%s✅ Responded to BOT command%s\n", ColorGreen, ColorReset)
-                                }
-                        case "menu":
-                                commands.HandleMenuCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender)
-                        case "info":
-                                commands.HandleInfoCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender)
-                        case "ping":
-                                commands.HandlePingCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender)
-                        case "online":
-                                if args == "on" {
-                                        core.UpdateConfig(&[]bool{true}[0], nil, nil, nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Auto Online DIAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Auto Online enabled%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(&[]bool{false}[0], nil, nil, nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Auto Online DINONAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Auto Online disabled%s\n", ColorYellow, ColorReset)
-                                }
-                        case "typing":
-                                if args == "on" {
-                                        core.UpdateConfig(nil, &[]bool{true}[0], nil, nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Auto Typing DIAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Auto Typing enabled%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(nil, &[]bool{false}[0], nil, nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Auto Typing DINONAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Auto Typing disabled%s\n", ColorYellow, ColorReset)
-                                }
-                        case "record":
-                                if args == "on" {
-                                        core.UpdateConfig(nil, nil, &[]bool{true}[0], nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Auto Recording DIAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Auto Recording enabled%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(nil, nil, &[]bool{false}[0], nil, nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Auto Recording DINONAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Auto Recording disabled%s\n", ColorYellow, ColorReset)
-                                }
-                        case "readstory":
-                                if args == "on" {
-                                        core.UpdateConfig(nil, nil, nil, &[]bool{true}[0], nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Auto Read Story DIAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Auto Read Story enabled%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(nil, nil, nil, &[]bool{false}[0], nil, nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Auto Read Story DINONAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Auto Read Story disabled%s\n", ColorYellow, ColorReset)
-                                }
-                        case "likestory":
-                                if args == "on" {
-                                        core.UpdateConfig(nil, nil, nil, nil, &[]bool{true}[0], nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Auto Like Story DIAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Auto Like Story enabled%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(nil, nil, nil, nil, &[]bool{false}[0], nil)
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Auto Like Story DINONAKTIFKAN"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Auto Like Story disabled%s\n", ColorYellow, ColorReset)
-                                }
-                        case "storydelay":
-                                if args == "on" {
-                                        core.UpdateConfig(nil, nil, nil, nil, nil, &[]bool{true}[0])
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("✅ Story Random Delay DIAKTIFKAN (1-20 detik)"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s✅ Story Random Delay enabled (1-20s)%s\n", ColorGreen, ColorReset)
-                                } else if args == "off" {
-                                        core.UpdateConfig(nil, nil, nil, nil, nil, &[]bool{false}[0])
-                                        replyMsg := &waProto.Message{
-                                                ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                        Text: proto.String("❌ Story Random Delay DINONAKTIFKAN (1 detik)"),
-                                                        ContextInfo: &waProto.ContextInfo{
-                                                                StanzaID:    proto.String(v.Info.ID),
-                                                                Participant: proto.String(v.Info.Sender.String()),
-                                                        },
-                                                },
-                                        }
-                                        client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                        fmt.Printf("%s❌ Story Random Delay disabled (1s)%s\n", ColorYellow, ColorReset)
-                                }
-                        case "status":
-                                config := core.GetConfig()
-                                onlineStatus := "OFF ❌"
-                                if config.AutoOnline {
-                                        onlineStatus = "ON ✅"
-                                }
-                                typingStatus := "OFF ❌"
-                                if config.AutoTyping {
-                                        typingStatus = "ON ✅"
-                                }
-                                recordStatus := "OFF ❌"
-                                if config.AutoRecording {
-                                        recordStatus = "ON ✅"
-                                }
-                                readStoryStatus := "OFF ❌"
-                                if config.AutoReadStory {
-                                        readStoryStatus = "ON ✅"
-                                }
-                                likeStoryStatus := "OFF ❌"
-                                if config.AutoLikeStory {
-                                        likeStoryStatus = "ON ✅"
-                                }
-                                storyDelayStatus := "Normal (1s) ❌"
-                                if config.StoryRandomDelay {
-                                        storyDelayStatus = "Random (1-20s) ✅"
-                                }
-                                statusText := fmt.Sprintf("📊 STATUS FITUR:\n\n🌐 Auto Online: %s\n🖊️ Auto Typing: %s\n🎤 Auto Recording: %s\n👁️ Auto Read Story: %s\n❤️ Auto Like Story: %s\n⏱️ Story Delay: %s", onlineStatus, typingStatus, recordStatus, readStoryStatus, likeStoryStatus, storyDelayStatus)
-                                replyMsg := &waProto.Message{
-                                        ExtendedTextMessage: &waProto.ExtendedTextMessage{
-                                                Text: proto.String(statusText),
-                                                ContextInfo: &waProto.ContextInfo{
-                                                        StanzaID:    proto.String(v.Info.ID),
-                                                        Participant: proto.String(v.Info.Sender.String()),
-                                                },
-                                        },
-                                }
-                                client.SendMessage(ctx, v.Info.Chat, replyMsg)
-                                fmt.Printf("%s📊 Status checked%s\n", ColorCyan, ColorReset)
-                        case "jadibot":
-                                features.HandleJadibotCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender, args)
-                                fmt.Printf("%s🤖 Jadibot command executed%s\n", ColorCyan, ColorReset)
-                        case "listjadibot":
-                                features.HandleListJadibotCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender)
-                                fmt.Printf("%s📋 List jadibot command executed%s\n", ColorCyan, ColorReset)
-                        case "deljadibot":
-                                features.HandleDelJadibotCommand(client, v.Info.Chat, v.Info.ID, v.Info.Sender, args)
-                                fmt.Printf("%s🗑️ Delete jadibot command executed%s\n", ColorCyan, ColorReset)
-                                }
-                        }
-                }
-        }
-}
-
 func isSessionValid(nomor string) bool {
         ctx := context.Background()
-        dbFilePath := "Wilykun/bossbot/" + nomor + ".db"
+        dbFilePath := core.GetPaths().MainSessionDB(nomor)
 
         if _, err := os.Stat(dbFilePath); os.IsNotExist(err) {
                 return false
         }
 
         dbLog := waLog.Stdout("Database", "ERROR", true)
-        dbPath := "file:" + dbFilePath + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(FULL)&_pragma=wal_autocheckpoint(100)"
-        container, err := sqlstore.New(ctx, "sqlite3", dbPath, dbLog)
+        container, err := sqlstore.New(ctx, "sqlite3", features.SessionDBURI(dbFilePath), dbLog)
         if err != nil {
                 return false
         }
@@ -796,7 +425,7 @@ func isSessionValid(nomor string) bool {
 }
 
 func cleanInvalidSession(nomor string) {
-        dbPath := "Wilykun/bossbot/" + nomor + ".db"
+        dbPath := core.GetPaths().MainSessionDB(nomor)
 
         os.Remove(dbPath)
         os.Remove(dbPath + "-shm")
@@ -805,31 +434,8 @@ func cleanInvalidSession(nomor string) {
         fmt.Printf("%s⚠️ Session tidak valid, file database dihapus otomatis%s\n", ColorYellow, ColorReset)
 }
 
-func checkpointDatabase(nomor string) {
-        dbFilePath := "Wilykun/bossbot/" + nomor + ".db"
-
-        if _, err := os.Stat(dbFilePath); os.IsNotExist(err) {
-                return
-        }
-
-        db, err := sql.Open("sqlite3", "file:"+dbFilePath+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)")
-        if err != nil {
-                fmt.Printf("%s⚠️ Gagal membuka database untuk checkpoint: %v%s\n", ColorYellow, err, ColorReset)
-                return
-        }
-        defer db.Close()
-
-        _, err = db.Exec("PRAGMA wal_checkpoint(TRUNCATE);")
-        if err != nil {
-                fmt.Printf("%s⚠️ Gagal checkpoint database: %v%s\n", ColorYellow, err, ColorReset)
-                return
-        }
-
-        fmt.Printf("%s✅ Database checkpoint berhasil%s\n", ColorGreen, ColorReset)
-}
-
 func getExistingPhoneNumbers() []string {
-        entries, err := os.ReadDir("Wilykun/bossbot")
+        entries, err := os.ReadDir(core.GetPaths().MainSessionDir())
         if err != nil {
                 return nil
         }
@@ -846,30 +452,17 @@ func getExistingPhoneNumbers() []string {
         return numbers
 }
 
-func initializeCoreFunctions() {
-        core.SetAutoTypingEnabled = features.SetAutoTypingEnabled
-        core.SetAutoRecordingEnabled = features.SetAutoRecordingEnabled
-        core.SetAutoReadStory = features.SetAutoReadStory
-        core.SetAutoLikeStory = features.SetAutoLikeStory
-        core.SetStoryRandomDelay = features.SetStoryRandomDelay
-}
-
-func main() {
+// interactiveStartup asks on stdin for whatever the config leaves open: the
+// main bot's number (or which existing session to use) and the pairing
+// method. It returns the number and the method (1 = code, 2 = QR).
+func interactiveStartup() (string, int) {
         var nomer string
         var pairingMethod int
         var sessionValid bool = false
 
-        initializeCoreFunctions()
-        core.InitConfig()
-        commands.BotStartTime = time.Now()
-        botStartTime = time.Now()
-
-        fmt.Print(ColorBold + ColorCyan + "\n🤖 WhatsApp Auto-React Bot Starter\n" + ColorReset)
-        fmt.Print(ColorYellow + "=" + strings.Repeat("=", 35) + ColorReset + "\n\n")
-
-        nomer = os.Getenv("WHATSAPP_NUMBER")
-        if nomer == "" {
-                nomer = os.Getenv("NOMOR_BOT")
+        nomer = core.Runtime().PhoneNumber
+        if nomer != "" {
+                nomer = normalizeMainNumber(nomer)
         }
 
         if nomer == "" {
@@ -910,7 +503,7 @@ func main() {
                                         sessionValid = true
                                 }
                         } else {
-                                nomer = choice
+                                nomer = normalizeMainNumber(choice)
                                 fmt.Print(ColorGreen + "✅ Nomor baru: " + ColorReset + ColorBold + nomer + ColorReset + "\n")
                         }
                 }
@@ -922,9 +515,10 @@ func main() {
                                 fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Nomor tidak boleh kosong!\n" + ColorReset)
                                 os.Exit(1)
                         }
+                        nomer = normalizeMainNumber(nomer)
                 }
         } else {
-                fmt.Print(ColorGreen + "✅ Nomor dari environment: " + ColorReset + ColorBold + nomer + ColorReset + "\n")
+                fmt.Print(ColorGreen + "✅ Nomor dari konfigurasi: " + ColorReset + ColorBold + nomer + ColorReset + "\n")
 
                 fmt.Print(ColorCyan + "🔍 Mengecek validitas session...\n" + ColorReset)
                 if !isSessionValid(nomer) {
@@ -936,6 +530,7 @@ func main() {
                                 fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Nomor tidak boleh kosong!\n" + ColorReset)
                                 os.Exit(1)
                         }
+                        nomer = normalizeMainNumber(nomer)
                 } else {
                         fmt.Print(ColorGreen + "✅ Session valid!\n" + ColorReset)
                         sessionValid = true
@@ -945,12 +540,8 @@ func main() {
         countryInfo := getCountryInfo(nomer)
         fmt.Print(ColorGreen + "✅ Negara: " + ColorReset + ColorBold + countryInfo.CountryName + ColorReset + "\n\n")
 
-        pairingMethodStr := os.Getenv("WHATSAPP_PAIRING_METHOD")
-        if pairingMethodStr != "" {
-                _, err := fmt.Sscanf(pairingMethodStr, "%d", &pairingMethod)
-                if err != nil || (pairingMethod != 1 && pairingMethod != 2) {
-                        pairingMethod = 2
-                }
+        pairingMethod = core.Runtime().PairingMethod
+        if pairingMethod != 0 {
                 methodName := "QR Code"
                 if pairingMethod == 1 {
                         methodName = "Kode Pairing"
@@ -960,25 +551,103 @@ func main() {
                 pairingMethod = 2
                 fmt.Print(ColorGreen + "✅ Metode Pairing: " + ColorReset + ColorBold + "QR Code (default)" + ColorReset + "\n\n")
         } else {
-                fmt.Print(ColorYellow + "Pilih metode pairing:\n" + ColorReset)
-                fmt.Print(ColorCyan + "1. Kode Pairing (Manual)\n" + ColorReset)
-                fmt.Print(ColorCyan + "2. QR Code (Scan dengan HP)\n" + ColorReset)
-                fmt.Print(ColorGreen + "Pilih (1/2): " + ColorReset)
-                fmt.Scanln(&pairingMethod)
-
-                if pairingMethod != 1 && pairingMethod != 2 {
-                        fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Pilihan tidak valid! Gunakan 1 atau 2\n" + ColorReset)
-                        os.Exit(1)
-                }
+                pairingMethod = askPairingMethod()
+        }
 
-                methodName := "QR Code"
-                if pairingMethod == 1 {
-                        methodName = "Kode Pairing"
+        return nomer, pairingMethod
+}
+
+// askPairingMethod asks on stdin how to pair: 1 = code, 2 = QR
+func askPairingMethod() int {
+        var pairingMethod int
+        fmt.Print(ColorYellow + "Pilih metode pairing:\n" + ColorReset)
+        fmt.Print(ColorCyan + "1. Kode Pairing (Manual)\n" + ColorReset)
+        fmt.Print(ColorCyan + "2. QR Code (Scan dengan HP)\n" + ColorReset)
+        fmt.Print(ColorGreen + "Pilih (1/2): " + ColorReset)
+        fmt.Scanln(&pairingMethod)
+
+        if pairingMethod != 1 && pairingMethod != 2 {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Pilihan tidak valid! Gunakan 1 atau 2\n" + ColorReset)
+                os.Exit(1)
+        }
+
+        methodName := "QR Code"
+        if pairingMethod == 1 {
+                methodName = "Kode Pairing"
+        }
+        fmt.Print(ColorGreen + "✅ Metode dipilih: " + ColorReset + ColorBold + methodName + ColorReset + "\n\n")
+        return pairingMethod
+}
+
+func main() {
+        var nomer string
+        var pairingMethod int
+
+        printConfig, err := core.LoadRuntimeConfig(os.Args[1:])
+        if err == flag.ErrHelp {
+                os.Exit(0)
+        }
+        if err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Konfigurasi tidak valid: " + err.Error() + ColorReset + "\n")
+                os.Exit(2)
+        }
+        if printConfig {
+                fmt.Print(core.FormatRuntimeConfig())
+                return
+        }
+        if err := core.GetPaths().EnsureDirs(); err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ " + err.Error() + ColorReset + "\n")
+                os.Exit(1)
+        }
+
+        if err := core.InitConfig(); err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal memuat setting: " + err.Error() + ColorReset + "\n")
+                os.Exit(1)
+        }
+        if err := core.InitRoles(); err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal memuat data role: " + err.Error() + ColorReset + "\n")
+                os.Exit(1)
+        }
+        if err := core.InitOverrides(); err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal memuat override chat/kontak: " + err.Error() + ColorReset + "\n")
+                os.Exit(1)
+        }
+        if err := core.InitProfiles(); err != nil {
+                fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal memuat profile: " + err.Error() + ColorReset + "\n")
+                os.Exit(1)
+        }
+        commands.BotStartTime = time.Now()
+        botStartTime = time.Now()
+
+        fmt.Print(ColorBold + ColorCyan + "\n🤖 WhatsApp Auto-React Bot Starter\n" + ColorReset)
+        fmt.Print(ColorYellow + "=" + strings.Repeat("=", 35) + ColorReset + "\n\n")
+
+        var accounts []string
+        switch {
+        case core.Runtime().MultiAccount:
+                accounts, pairingMethod = multiAccountStartup()
+                if err := core.InitAccounts(accounts); err != nil {
+                        fmt.Print(ColorReset + ColorBold + ColorYellow + "❌ Gagal memuat setting akun: " + err.Error() + ColorReset + "\n")
+                        os.Exit(1)
                 }
-                fmt.Print(ColorGreen + "✅ Metode dipilih: " + ColorReset + ColorBold + methodName + ColorReset + "\n\n")
+        case core.Runtime().Headless:
+                nomer, pairingMethod = headlessStartup()
+                accounts = []string{nomer}
+        default:
+                nomer, pairingMethod = interactiveStartup()
+                accounts = []string{nomer}
+        }
+        if addr := core.Runtime().PairingAddr; addr != "" && core.Runtime().Headless {
+                servePairing(addr)
         }
 
-        go Connect(nomer, mes, pairingMethod == 2)
+        // Every account's commands go through the same router; jadibot
+        // accounts only run their own (.menu, .likestory, .stop)
+        features.GetSessionManager().SetMessageHandler(commands.HandleMessage)
+
+        for _, nomor := range accounts {
+                go Connect(nomor, pairingMethod == 2)
+        }
 
         go func() {
                 time.Sleep(5 * time.Second)
@@ -991,7 +660,7 @@ func main() {
         <-c
 
         fmt.Println("\n" + ColorYellow + "⚠️ Menerima signal shutdown, menyimpan data..." + ColorReset)
-        checkpointDatabase(nomer)
+        features.GetSessionManager().CheckpointAll()
         fmt.Println(ColorGreen + "✅ Data tersimpan dengan aman!" + ColorReset)
         os.Exit(0)
 }
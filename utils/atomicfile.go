@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic replaces path with data without ever leaving a half
+// written file behind: the data goes to a temp file in the same folder,
+// is fsynced, and is then renamed over path. The previous content, if any,
+// is kept as path + ".bak", written the same way so a crash can't leave a
+// truncated backup.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("gagal membuat folder %s: %w", dir, err)
+	}
+
+	if old, err := os.ReadFile(path); err == nil {
+		if err := replaceFile(path+".bak", old, perm); err != nil {
+			return fmt.Errorf("gagal membuat backup %s.bak: %w", path, err)
+		}
+	}
+	return replaceFile(path, data, perm)
+}
+
+// replaceFile writes data to a temp file next to path, fsyncs it and
+// renames it over path
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("gagal membuat file sementara: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal menulis %s: %w", tmpName, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("gagal fsync %s: %w", tmpName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("gagal menutup %s: %w", tmpName, err)
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return fmt.Errorf("gagal chmod %s: %w", tmpName, err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("gagal mengganti %s: %w", path, err)
+	}
+
+	// fsync the folder so the rename itself survives a crash
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
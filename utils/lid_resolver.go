@@ -355,3 +355,35 @@ func GetAccurateSenderInfo(client *whatsmeow.Client, senderJID types.JID, chatJI
 
 	return info
 }
+
+// ContactIDs returns every identifier a contact may be stored under in the
+// bot's own files: the phone number digits and, for LID contacts, the LID
+// JID as well. phone is "" when a LID can't be mapped to a number.
+func ContactIDs(client *whatsmeow.Client, jid types.JID) (ids []string, phone string) {
+	jid = jid.ToNonAD()
+
+	switch jid.Server {
+	case types.DefaultUserServer:
+		phone = jid.User
+		ids = append(ids, phone)
+		if lid := GetLIDForPN(jid.String()); lid != "" {
+			ids = append(ids, lid)
+		}
+	case types.HiddenUserServer:
+		ids = append(ids, jid.String())
+		pn := GetPNForLID(jid.String())
+		if pn == "" && client != nil && client.Store.LIDs != nil {
+			if pnJID, err := client.Store.LIDs.GetPNForLID(context.Background(), jid); err == nil && !pnJID.IsEmpty() {
+				pn = pnJID.String()
+				StoreLIDMapping(jid.String(), pn)
+			}
+		}
+		if parsed, err := types.ParseJID(pn); err == nil && pn != "" {
+			phone = parsed.User
+			ids = append(ids, phone)
+		}
+	default:
+		ids = append(ids, jid.String())
+	}
+	return ids, phone
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+// PhoneNumber is a phone number validated with libphonenumber
+type PhoneNumber struct {
+	// Number is the international number without "+", as used in JIDs
+	// and jadibot folder names (e.g. "6281234567890")
+	Number string
+	// Region is the ISO 3166 region code of the number (e.g. "ID")
+	Region string
+}
+
+// Country returns the country name with its flag, e.g. "🇮🇩 Indonesia"
+func (p PhoneNumber) Country() string {
+	if name, ok := countryNames[p.Region]; ok {
+		return name
+	}
+	if p.Region == "" || p.Region == "ZZ" {
+		return "🌍 Internasional"
+	}
+	return p.Flag() + " " + p.Region
+}
+
+// Flag returns the flag emoji of the number's region, built from the two
+// regional indicator letters of its code, or "🌍" for non-geographic numbers
+func (p PhoneNumber) Flag() string {
+	region := strings.ToUpper(p.Region)
+	if len(region) != 2 || region == "ZZ" || region[0] < 'A' || region[0] > 'Z' || region[1] < 'A' || region[1] > 'Z' {
+		return "🌍"
+	}
+	return string([]rune{0x1F1E6 + rune(region[0]-'A'), 0x1F1E6 + rune(region[1]-'A')})
+}
+
+// ParsePhoneNumber validates a phone number typed by a user and returns it
+// in international form. Numbers starting with "+" or "00", or that are
+// already valid with their country code (e.g. "6281234567890"), are read
+// as international. Anything else, like "081234567890", is read as a local
+// number of defaultRegion.
+func ParsePhoneNumber(input, defaultRegion string) (PhoneNumber, error) {
+	var digits strings.Builder
+	for _, r := range strings.TrimSpace(input) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' || r == '-' || r == ' ' || r == '(' || r == ')' || r == '.':
+		default:
+			return PhoneNumber{}, fmt.Errorf("nomor %q mengandung karakter tidak valid", input)
+		}
+	}
+	number := digits.String()
+	if number == "" {
+		return PhoneNumber{}, fmt.Errorf("nomor tidak boleh kosong")
+	}
+
+	international := strings.HasPrefix(strings.TrimSpace(input), "+") || strings.HasPrefix(number, "00")
+	number = strings.TrimPrefix(number, "00")
+
+	var candidates []*phonenumbers.PhoneNumber
+	if parsed, err := phonenumbers.Parse("+"+number, ""); err == nil {
+		candidates = append(candidates, parsed)
+	}
+	if !international && defaultRegion != "" {
+		if parsed, err := phonenumbers.Parse(number, strings.ToUpper(defaultRegion)); err == nil {
+			candidates = append(candidates, parsed)
+		}
+	}
+
+	for _, parsed := range candidates {
+		if phonenumbers.IsValidNumber(parsed) {
+			return PhoneNumber{
+				Number: strings.TrimPrefix(phonenumbers.Format(parsed, phonenumbers.E164), "+"),
+				Region: phonenumbers.GetRegionCodeForNumber(parsed),
+			}, nil
+		}
+	}
+	if international || defaultRegion == "" {
+		return PhoneNumber{}, fmt.Errorf("nomor %q tidak valid, gunakan kode negara (contoh: 6281234567890)", input)
+	}
+	return PhoneNumber{}, fmt.Errorf("nomor %q tidak valid untuk kode negara maupun format lokal %s", input, strings.ToUpper(defaultRegion))
+}
+
+// IsSupportedRegion reports whether region is a region code libphonenumber
+// knows, e.g. "ID" or "MY"
+func IsSupportedRegion(region string) bool {
+	_, ok := phonenumbers.GetSupportedRegions()[strings.ToUpper(region)]
+	return ok
+}
+
+// countryNames are the display names of the most common regions; others
+// are shown by region code
+var countryNames = map[string]string{
+	"ID": "🇮🇩 Indonesia",
+	"US": "🇺🇸 United States",
+	"GB": "🇬🇧 United Kingdom",
+	"FR": "🇫🇷 France",
+	"DE": "🇩🇪 Germany",
+	"IT": "🇮🇹 Italy",
+	"ES": "🇪🇸 Spain",
+	"NL": "🇳🇱 Netherlands",
+	"BE": "🇧🇪 Belgium",
+	"CH": "🇨🇭 Switzerland",
+	"AT": "🇦🇹 Austria",
+	"PL": "🇵🇱 Poland",
+	"SE": "🇸🇪 Sweden",
+	"NO": "🇳🇴 Norway",
+	"DK": "🇩🇰 Denmark",
+	"FI": "🇫🇮 Finland",
+	"RU": "🇷🇺 Russia",
+	"UA": "🇺🇦 Ukraine",
+	"TR": "🇹🇷 Turkey",
+	"GR": "🇬🇷 Greece",
+	"PT": "🇵🇹 Portugal",
+	"CZ": "🇨🇿 Czech Republic",
+	"HU": "🇭🇺 Hungary",
+	"RO": "🇷🇴 Romania",
+	"BG": "🇧🇬 Bulgaria",
+	"HR": "🇭🇷 Croatia",
+	"SI": "🇸🇮 Slovenia",
+	"SK": "🇸🇰 Slovakia",
+	"LT": "🇱🇹 Lithuania",
+	"LV": "🇱🇻 Latvia",
+	"EE": "🇪🇪 Estonia",
+	"JP": "🇯🇵 Japan",
+	"KR": "🇰🇷 South Korea",
+	"CN": "🇨🇳 China",
+	"IN": "🇮🇳 India",
+	"TH": "🇹🇭 Thailand",
+	"MY": "🇲🇾 Malaysia",
+	"SG": "🇸🇬 Singapore",
+	"PH": "🇵🇭 Philippines",
+	"VN": "🇻🇳 Vietnam",
+	"BD": "🇧🇩 Bangladesh",
+	"PK": "🇵🇰 Pakistan",
+	"AU": "🇦🇺 Australia",
+	"NZ": "🇳🇿 New Zealand",
+	"CA": "🇨🇦 Canada",
+	"MX": "🇲🇽 Mexico",
+	"BR": "🇧🇷 Brazil",
+	"AR": "🇦🇷 Argentina",
+	"CL": "🇨🇱 Chile",
+	"CO": "🇨🇴 Colombia",
+	"PE": "🇵🇪 Peru",
+	"ZA": "🇿🇦 South Africa",
+	"EG": "🇪🇬 Egypt",
+	"NG": "🇳🇬 Nigeria",
+	"KE": "🇰🇪 Kenya",
+}